@@ -68,3 +68,18 @@ func ExampleParseResourceID() {
 	// Parent: /subscriptions/00000000-0000-0000-0000-000000000000
 	// Name: 00000000-0000-0000-0000-000000000000, ResourceType: Microsoft.Resources/subscriptions, SubscriptionId: 00000000-0000-0000-0000-000000000000, ResourceGroupName:
 }
+
+func ExampleNewSubscriptionID() {
+	vnet := NewSubscriptionID("00000000-0000-0000-0000-000000000000").
+		ResourceGroup("myRg").
+		ProviderNamespace("Microsoft.Network").
+		Child("virtualNetworks", "vnet")
+	subnet := vnet.Child("subnets", "mySub")
+
+	fmt.Println(subnet.String())
+	fmt.Println(subnet.Ancestor(ResourceGroupResourceType).Name)
+
+	// Output:
+	// /subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myRg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/mySub
+	// myRg
+}
@@ -7,6 +7,8 @@
 package policy
 
 import (
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
@@ -40,10 +42,140 @@ type RegistrationOptions struct {
 	PollingDuration time.Duration
 }
 
+// BatchOptions configures the coalescing of GET requests into Azure Resource Manager batch requests.
+type BatchOptions struct {
+	// Window is how long a GET request waits for others to send with it in a batch request.
+	// Batching is disabled when Window is zero, the default.
+	Window time.Duration
+
+	// MaxBatchSize is the maximum number of requests in a batch request.
+	// The default value is 20.
+	MaxBatchSize int
+}
+
+// QuotaUpdate is the remaining request quota Azure Resource Manager reported in a response's
+// x-ms-ratelimit-remaining-* headers.
+type QuotaUpdate struct {
+	// SubscriptionID is the subscription of the request. It's empty for requests at tenant scope.
+	SubscriptionID string
+
+	// Remaining maps the name of each quota, such as "subscription-reads" or "tenant-writes",
+	// to the number of requests remaining before the service throttles requests.
+	Remaining map[string]int
+
+	// Time is when the response was received.
+	Time time.Time
+}
+
+// ThrottlingOptions configures the throttling policy's behavior.
+// The policy tracks the remaining request quota of each subscription and the tenant, and can delay
+// requests before a quota is exhausted so they aren't throttled.
+type ThrottlingOptions struct {
+	// Threshold is the remaining quota below which the policy delays requests that count against the quota.
+	// The delay grows linearly from zero at Threshold to MaxDelay when no quota remains.
+	// The default value of zero disables delaying requests.
+	Threshold int
+
+	// MaxDelay is the maximum amount of time to delay a request.
+	// The default value is 10 seconds.
+	MaxDelay time.Duration
+
+	// OnUpdate is called with the remaining quota reported by each response that has any.
+	// It must be safe for concurrent use.
+	OnUpdate func(QuotaUpdate)
+
+	// Tracker stores the remaining quota. Clients with the same Tracker share the quota they observe, and
+	// the application can read it with Tracker.Quota. When nil, each client tracks quota on its own.
+	Tracker *QuotaTracker
+}
+
+// QuotaTracker stores the request quota Azure Resource Manager reports remaining in each subscription
+// and the tenant. It's safe for concurrent use. Don't use this type directly, use NewQuotaTracker() instead.
+type QuotaTracker struct {
+	mu sync.RWMutex
+	// quotas maps lowercase subscription IDs to their remaining quota; tenant-scoped quota is in tenant
+	quotas map[string]QuotaUpdate
+	tenant QuotaUpdate
+}
+
+// NewQuotaTracker creates a QuotaTracker with no reported quota.
+func NewQuotaTracker() *QuotaTracker {
+	return &QuotaTracker{quotas: map[string]QuotaUpdate{}}
+}
+
+// Quota returns the remaining quota most recently reported for the specified subscription.
+// The returned value includes tenant-scoped quotas. Pass an empty subscriptionID to get only the
+// tenant-scoped quotas. Returns false when no quota has been reported.
+func (t *QuotaTracker) Quota(subscriptionID string) (QuotaUpdate, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	q, ok := t.quotas[strings.ToLower(subscriptionID)]
+	if subscriptionID == "" {
+		q, ok = t.tenant, len(t.tenant.Remaining) > 0
+	}
+	if !ok && len(t.tenant.Remaining) == 0 {
+		return QuotaUpdate{}, false
+	}
+	result := mergeQuota(t.tenant, q)
+	result.SubscriptionID = subscriptionID
+	if t.tenant.Time.After(result.Time) {
+		result.Time = t.tenant.Time
+	}
+	return result, true
+}
+
+// Update stores the remaining quota in u, replacing any previously reported values of the same quotas.
+// The throttling policy calls it with the quota reported by each response.
+func (t *QuotaTracker) Update(u QuotaUpdate) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var tenant, sub map[string]int
+	for k, v := range u.Remaining {
+		if u.SubscriptionID != "" && !strings.HasPrefix(k, "tenant-") {
+			if sub == nil {
+				sub = map[string]int{}
+			}
+			sub[k] = v
+		} else {
+			if tenant == nil {
+				tenant = map[string]int{}
+			}
+			tenant[k] = v
+		}
+	}
+	if tenant != nil {
+		t.tenant = mergeQuota(t.tenant, QuotaUpdate{Remaining: tenant, Time: u.Time})
+	}
+	if sub != nil {
+		key := strings.ToLower(u.SubscriptionID)
+		t.quotas[key] = mergeQuota(t.quotas[key], QuotaUpdate{SubscriptionID: u.SubscriptionID, Remaining: sub, Time: u.Time})
+	}
+}
+
+// mergeQuota returns a copy of q with the values in u.
+func mergeQuota(q, u QuotaUpdate) QuotaUpdate {
+	merged := QuotaUpdate{SubscriptionID: u.SubscriptionID, Remaining: map[string]int{}, Time: u.Time}
+	for k, v := range q.Remaining {
+		merged.Remaining[k] = v
+	}
+	for k, v := range u.Remaining {
+		merged.Remaining[k] = v
+	}
+	return merged
+}
+
 // ClientOptions contains configuration settings for a client's pipeline.
 type ClientOptions struct {
 	policy.ClientOptions
 
+	// Batch configures the coalescing of concurrent GET requests into batch requests,
+	// which reduces round trips when getting many resources. Batching is disabled by default.
+	Batch BatchOptions
+
 	// DisableRPRegistration disables the auto-RP registration policy. Defaults to false.
 	DisableRPRegistration bool
+
+	// Throttling configures tracking of the request quota remaining in each subscription.
+	// It's disabled by default. Set Threshold, OnUpdate or Tracker to enable it.
+	Throttling ThrottlingOptions
 }
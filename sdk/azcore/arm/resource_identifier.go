@@ -27,7 +27,8 @@ var RootResourceID = &ResourceID{
 }
 
 // ResourceID represents a resource ID such as `/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myRg`.
-// Don't create this type directly, use ParseResourceID instead.
+// Don't create this type directly, use ParseResourceID or the builder methods starting with NewSubscriptionID
+// or RootResourceID instead.
 type ResourceID struct {
 	// Parent is the parent ResourceID of this instance.
 	// Can be nil if there is no parent.
@@ -110,6 +111,58 @@ func (id *ResourceID) String() string {
 	return id.stringValue
 }
 
+// Equal returns true when id and other identify the same resource. Resource IDs are case-insensitive.
+func (id *ResourceID) Equal(other *ResourceID) bool {
+	if id == nil || other == nil {
+		return id == other
+	}
+	return strings.EqualFold(id.String(), other.String())
+}
+
+// Ancestor returns the nearest ID having the specified resource type in the chain from id to the root, including id
+// itself, for example the virtual network of a subnet. It returns nil when there's no such ID.
+func (id *ResourceID) Ancestor(resourceType ResourceType) *ResourceID {
+	for current := id; current != nil; current = current.Parent {
+		if current.ResourceType.Equal(resourceType) {
+			return current
+		}
+	}
+	return nil
+}
+
+// ExtensionScope returns the ID of the resource extended by id when id is, or is a child of, an extension resource
+// such as a lock on a virtual machine. It returns nil when id doesn't extend another resource.
+func (id *ResourceID) ExtensionScope() *ResourceID {
+	// find the top-level resource of id's provider
+	top := id
+	for top != nil && top.isChild {
+		top = top.Parent
+	}
+	if top == nil || top.Parent == nil {
+		return nil
+	}
+	switch top.Parent.ResourceType.String() {
+	case TenantResourceType.String(), SubscriptionResourceType.String(), ResourceGroupResourceType.String(), ProviderResourceType.String():
+		return nil
+	}
+	return top.Parent
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (id ResourceID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (id *ResourceID) UnmarshalText(text []byte) error {
+	parsed, err := ParseResourceID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = *parsed
+	return nil
+}
+
 func newResourceID(parent *ResourceID, resourceTypeName string, resourceName string) *ResourceID {
 	id := &ResourceID{}
 	id.init(parent, chooseResourceType(resourceTypeName, parent), resourceName, true)
@@ -0,0 +1,52 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package arm
+
+// NewSubscriptionID creates the ResourceID of a subscription, from which the IDs of its resource groups
+// and resources can be built. For example, the ID of a virtual machine:
+//
+//	id := NewSubscriptionID(subscriptionID).ResourceGroup("myRg").ProviderNamespace("Microsoft.Compute").Child("virtualMachines", "myVM")
+//
+// The builder methods don't validate their arguments. Use ParseResourceID to validate an ID from an untrusted source.
+func NewSubscriptionID(subscriptionID string) *ResourceID {
+	return newResourceIDWithResourceType(RootResourceID, SubscriptionResourceType, subscriptionID)
+}
+
+// ResourceGroup returns the ResourceID of the resource group with the specified name in the subscription id.
+func (id *ResourceID) ResourceGroup(name string) *ResourceID {
+	return newResourceIDWithResourceType(id, ResourceGroupResourceType, name)
+}
+
+// ProviderNamespace returns the scope of the resource provider namespace, for example "Microsoft.Compute", within id.
+// id is a tenant, subscription or resource group, or a resource when building the ID of an extension resource.
+func (id *ResourceID) ProviderNamespace(namespace string) ProviderScope {
+	return ProviderScope{parent: id, namespace: namespace}
+}
+
+// Child returns the ResourceID of the child resource of id having the specified type and name, for example
+// the "subnets" of a virtual network. Pass an empty name for a child resource type that has no name.
+func (id *ResourceID) Child(resourceType, name string) *ResourceID {
+	return newResourceID(id, resourceType, name)
+}
+
+// ProviderScope is a resource provider namespace within a scope such as a resource group.
+// Use ResourceID.ProviderNamespace to create one.
+type ProviderScope struct {
+	parent    *ResourceID
+	namespace string
+}
+
+// Child returns the ResourceID of the top-level resource of the provider having the specified type and name.
+func (p ProviderScope) Child(resourceType, name string) *ResourceID {
+	return newResourceIDWithProvider(p.parent, p.namespace, resourceType, name)
+}
+
+// ID returns the ResourceID of the provider, for example "/subscriptions/{id}/providers/Microsoft.Compute".
+// Providers have IDs only within a tenant or subscription.
+func (p ProviderScope) ID() *ResourceID {
+	return newResourceIDWithResourceType(p.parent, ProviderResourceType, p.namespace)
+}
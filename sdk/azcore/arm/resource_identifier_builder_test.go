@@ -0,0 +1,174 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package arm
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const testSubscriptionID = "17fecd63-33d8-4e43-ac6f-0aafa111b38d"
+
+func TestResourceIDBuilder(t *testing.T) {
+	sub := NewSubscriptionID(testSubscriptionID)
+	rg := sub.ResourceGroup("myRg")
+	vnet := rg.ProviderNamespace("Microsoft.Network").Child("virtualNetworks", "myVnet")
+	for expected, id := range map[string]*ResourceID{
+		"/subscriptions/" + testSubscriptionID:                                                                                                                            sub,
+		"/subscriptions/" + testSubscriptionID + "/resourceGroups/myRg":                                                                                                   rg,
+		"/subscriptions/" + testSubscriptionID + "/providers/Microsoft.Insights":                                                                                          sub.ProviderNamespace("Microsoft.Insights").ID(),
+		"/subscriptions/" + testSubscriptionID + "/locations/westus":                                                                                                      sub.Child("locations", "westus"),
+		"/providers/Microsoft.Management/managementGroups/myMg":                                                                                                           RootResourceID.ProviderNamespace("Microsoft.Management").Child("managementGroups", "myMg"),
+		"/subscriptions/" + testSubscriptionID + "/resourceGroups/myRg/providers/Microsoft.Network/virtualNetworks/myVnet":                                                vnet,
+		"/subscriptions/" + testSubscriptionID + "/resourceGroups/myRg/providers/Microsoft.Network/virtualNetworks/myVnet/subnets/mySubnet":                               vnet.Child("subnets", "mySubnet"),
+		"/subscriptions/" + testSubscriptionID + "/resourceGroups/myRg/providers/Microsoft.Network/virtualNetworks/myVnet/providers/Microsoft.Authorization/locks/myLock": vnet.ProviderNamespace("Microsoft.Authorization").Child("locks", "myLock"),
+	} {
+		t.Run(expected, func(t *testing.T) {
+			if s := id.String(); s != expected {
+				t.Fatalf("expected %s, got %s", expected, s)
+			}
+			parsed, err := ParseResourceID(expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// String caches the string of each ID in the chain
+			_ = parsed.String()
+			if !reflect.DeepEqual(parsed, id) {
+				t.Fatalf("expected %+v, got %+v", parsed, id)
+			}
+		})
+	}
+	if name := vnet.ResourceGroupName; name != "myRg" {
+		t.Fatalf("unexpected resource group name %s", name)
+	}
+	if id := vnet.SubscriptionID; id != testSubscriptionID {
+		t.Fatalf("unexpected subscription ID %s", id)
+	}
+}
+
+func TestResourceIDEqual(t *testing.T) {
+	a, err := ParseResourceID("/subscriptions/" + testSubscriptionID + "/resourceGroups/MYRG/providers/microsoft.network/virtualNetworks/myVnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewSubscriptionID(testSubscriptionID).ResourceGroup("myRg").ProviderNamespace("Microsoft.Network").Child("virtualNetworks", "myvnet")
+	if !a.Equal(b) || !b.Equal(a) {
+		t.Fatal("expected IDs to be equal")
+	}
+	if a.Equal(b.Parent) {
+		t.Fatal("expected IDs not to be equal")
+	}
+	if a.Equal(nil) {
+		t.Fatal("expected ID not to equal nil")
+	}
+	var nilID *ResourceID
+	if !nilID.Equal(nil) {
+		t.Fatal("expected nil IDs to be equal")
+	}
+}
+
+func TestResourceIDAncestor(t *testing.T) {
+	id, err := ParseResourceID("/subscriptions/" + testSubscriptionID + "/resourceGroups/myRg/providers/Microsoft.Network/virtualNetworks/myVnet/subnets/mySubnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vnet := id.Ancestor(NewResourceType("microsoft.network", "virtualnetworks"))
+	if vnet == nil || vnet.String() != "/subscriptions/"+testSubscriptionID+"/resourceGroups/myRg/providers/Microsoft.Network/virtualNetworks/myVnet" {
+		t.Fatalf("unexpected ancestor %v", vnet)
+	}
+	if rg := id.Ancestor(ResourceGroupResourceType); rg == nil || rg.Name != "myRg" {
+		t.Fatalf("unexpected ancestor %v", rg)
+	}
+	if self := id.Ancestor(id.ResourceType); self != id {
+		t.Fatalf("expected the ID itself, got %v", self)
+	}
+	if ancestor := id.Ancestor(NewResourceType("Microsoft.Compute", "virtualMachines")); ancestor != nil {
+		t.Fatalf("unexpected ancestor %v", ancestor)
+	}
+}
+
+func TestResourceIDExtensionScope(t *testing.T) {
+	for id, expected := range map[string]string{
+		"/subscriptions/" + testSubscriptionID + "/resourceGroups/myRg/providers/Microsoft.Compute/virtualMachines/myVM/providers/Microsoft.Authorization/locks/myLock":                       "/subscriptions/" + testSubscriptionID + "/resourceGroups/myRg/providers/Microsoft.Compute/virtualMachines/myVM",
+		"/subscriptions/" + testSubscriptionID + "/resourceGroups/myRg/providers/Microsoft.Compute/virtualMachines/myVM/providers/Microsoft.Insights/diagnosticSettings/mySetting/child/name": "/subscriptions/" + testSubscriptionID + "/resourceGroups/myRg/providers/Microsoft.Compute/virtualMachines/myVM",
+		"/subscriptions/" + testSubscriptionID + "/resourceGroups/myRg/providers/Microsoft.Compute/virtualMachines/myVM":                                                                      "",
+		"/subscriptions/" + testSubscriptionID + "/resourceGroups/myRg/providers/Microsoft.Network/virtualNetworks/myVnet/subnets/mySubnet":                                                   "",
+		"/subscriptions/" + testSubscriptionID + "/providers/Microsoft.Authorization/locks/myLock":                                                                                            "",
+		"/subscriptions/" + testSubscriptionID + "/resourceGroups/myRg":                                                                                                                       "",
+		"/providers/Microsoft.Billing/billingAccounts/myAccount":                                                                                                                              "",
+	} {
+		t.Run(id, func(t *testing.T) {
+			parsed, err := ParseResourceID(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			scope := parsed.ExtensionScope()
+			if expected == "" {
+				if scope != nil {
+					t.Fatalf("unexpected scope %s", scope)
+				}
+			} else if scope == nil || scope.String() != expected {
+				t.Fatalf("expected scope %s, got %v", expected, scope)
+			}
+		})
+	}
+}
+
+func TestResourceIDMarshalling(t *testing.T) {
+	type resource struct {
+		ID   *ResourceID  `json:"id"`
+		Type ResourceType `json:"type"`
+	}
+	const raw = `{"id":"/subscriptions/` + testSubscriptionID + `/resourceGroups/myRg/providers/Microsoft.Network/virtualNetworks/myVnet","type":"Microsoft.Network/virtualNetworks"}`
+	var r resource
+	if err := json.Unmarshal([]byte(raw), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.ID.ResourceGroupName != "myRg" || r.ID.Name != "myVnet" {
+		t.Fatalf("unexpected ID %+v", r.ID)
+	}
+	if !r.Type.Equal(r.ID.ResourceType) {
+		t.Fatalf("unexpected type %s", r.Type)
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != raw {
+		t.Fatalf("unexpected JSON %s", b)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"not an ID"}`), &r); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// a value field round-trips as a string too
+	type valueResource struct {
+		ID ResourceID `json:"id"`
+	}
+	const valueRaw = `{"id":"/subscriptions/` + testSubscriptionID + `/resourceGroups/myRg"}`
+	var v valueResource
+	if err := json.Unmarshal([]byte(valueRaw), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.ID.ResourceGroupName != "myRg" {
+		t.Fatalf("unexpected ID %+v", v.ID)
+	}
+	if b, err = json.Marshal(v); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != valueRaw {
+		t.Fatalf("unexpected JSON %s", b)
+	}
+	// a nil pointer marshals as null
+	if b, err = json.Marshal(resource{}); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"id":null,"type":""}` {
+		t.Fatalf("unexpected JSON %s", b)
+	}
+}
@@ -46,6 +46,26 @@ func (t ResourceType) String() string {
 	return t.stringValue
 }
 
+// Equal returns true when t and other are the same resource type. Resource types are case-insensitive.
+func (t ResourceType) Equal(other ResourceType) bool {
+	return strings.EqualFold(t.String(), other.String())
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (t ResourceType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (t *ResourceType) UnmarshalText(text []byte) error {
+	parsed, err := ParseResourceType(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
 // IsParentOf returns true when the receiver is the parent resource type of the child.
 func (t ResourceType) IsParentOf(child ResourceType) bool {
 	if !strings.EqualFold(t.Namespace, child.Namespace) {
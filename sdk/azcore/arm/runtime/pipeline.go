@@ -29,9 +29,21 @@ func NewPipeline(module, version string, cred azcore.TokenCredential, plOpts azr
 		return azruntime.Pipeline{}, err
 	}
 	authPolicy := NewBearerTokenPolicy(cred, &armpolicy.BearerTokenOptions{Scopes: []string{conf.Audience + "/.default"}})
-	perRetry := make([]azpolicy.Policy, 0, len(plOpts.PerRetry)+1)
-	copy(perRetry, plOpts.PerRetry)
-	plOpts.PerRetry = append(perRetry, authPolicy)
+	perRetry := make([]azpolicy.Policy, 0, len(plOpts.PerRetry)+3)
+	perRetry = append(perRetry, plOpts.PerRetry...)
+	if options.Throttling.Threshold > 0 || options.Throttling.OnUpdate != nil || options.Throttling.Tracker != nil {
+		perRetry = append(perRetry, NewThrottlingPolicy(&options.Throttling))
+	}
+	perRetry = append(perRetry, authPolicy)
+	if options.Batch.Window > 0 {
+		// batch requests follow the auth policy so they carry the first request's authorization
+		batchPolicy, err := newBatchPolicy(conf.Endpoint, options.Batch)
+		if err != nil {
+			return azruntime.Pipeline{}, err
+		}
+		perRetry = append(perRetry, batchPolicy)
+	}
+	plOpts.PerRetry = perRetry
 	if !options.DisableRPRegistration {
 		regRPOpts := armpolicy.RegistrationOptions{ClientOptions: options.ClientOptions}
 		regPolicy, err := NewRPRegistrationPolicy(cred, &regRPOpts)
@@ -39,7 +51,7 @@ func NewPipeline(module, version string, cred azcore.TokenCredential, plOpts azr
 			return azruntime.Pipeline{}, err
 		}
 		perCall := make([]azpolicy.Policy, 0, len(plOpts.PerCall)+1)
-		copy(perCall, plOpts.PerCall)
+		perCall = append(perCall, plOpts.PerCall...)
 		plOpts.PerCall = append(perCall, regPolicy)
 	}
 	if plOpts.APIVersion.Name == "" {
@@ -115,6 +115,7 @@ func TestDisableAutoRPRegistration(t *testing.T) {
 	defer func() {
 		// reset logging
 		log.SetEvents()
+		log.SetListener(nil)
 	}()
 	logEntries := 0
 	log.SetListener(func(cls log.Event, msg string) {
@@ -187,6 +188,35 @@ func TestPipelineWithCustomPolicies(t *testing.T) {
 	}
 }
 
+func TestPipelineWithPipelineOptionsPolicies(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	// initial response is a failure to trigger retry
+	srv.AppendResponse(mock.WithStatusCode(http.StatusInternalServerError))
+	srv.AppendResponse(mock.WithStatusCode(http.StatusOK))
+	perCallPolicy := countingPolicy{}
+	perRetryPolicy := countingPolicy{}
+	plOpts := azruntime.PipelineOptions{
+		PerCall:  []policy.Policy{&perCallPolicy},
+		PerRetry: []policy.Policy{&perRetryPolicy},
+	}
+	opts := &armpolicy.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Retry:     policy.RetryOptions{RetryDelay: time.Microsecond},
+			Transport: srv,
+		},
+	}
+	pl, err := NewPipeline("armtest", "v1.2.3", mockCredential{}, plOpts, opts)
+	require.NoError(t, err)
+	req, err := azruntime.NewRequest(context.Background(), http.MethodGet, srv.URL())
+	require.NoError(t, err)
+	resp, err := pl.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 1, perCallPolicy.count)
+	require.Equal(t, 2, perRetryPolicy.count)
+}
+
 func TestPipelineAudience(t *testing.T) {
 	for _, c := range []cloud.Configuration{cloud.AzureChina, cloud.AzureGovernment, cloud.AzurePublic} {
 		srv, close := mock.NewServer()
@@ -0,0 +1,309 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	armpolicy "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	azpolicy "github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/log"
+)
+
+const (
+	// LogBatch entries contain information specific to the batching of requests.
+	// Entries of this classification are written when the policy sends a batch request.
+	LogBatch log.Event = "Batch"
+)
+
+const (
+	batchAPIVersion          = "2020-06-01"
+	batchPath                = "/batch"
+	defaultBatchMaxSize      = 20
+	defaultBatchPollingDelay = time.Second
+)
+
+// batchPolicy coalesces concurrent GET requests to the Resource Manager endpoint into batch requests.
+// The first request of a batch waits for the batch window, then sends the batch request on behalf of
+// every request in it and distributes the responses.
+type batchPolicy struct {
+	host    string
+	options armpolicy.BatchOptions
+
+	mu      sync.Mutex
+	pending *batch
+}
+
+// batch is a set of requests to send in one batch request.
+type batch struct {
+	items []*batchItem
+	// full is closed when the batch reaches its maximum size
+	full chan struct{}
+}
+
+// batchItem is a request in a batch.
+type batchItem struct {
+	req  *azpolicy.Request
+	done chan struct{}
+
+	// the following are set before done is closed
+
+	resp *http.Response
+	err  error
+	// fallback is true when the batch request couldn't be sent because the
+	// first request's context was cancelled, so the request must be sent alone
+	fallback bool
+}
+
+func newBatchPolicy(endpoint string, o armpolicy.BatchOptions) (*batchPolicy, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if o.MaxBatchSize <= 0 {
+		o.MaxBatchSize = defaultBatchMaxSize
+	}
+	return &batchPolicy{host: u.Host, options: o}, nil
+}
+
+func (b *batchPolicy) Do(req *azpolicy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	if raw.Method != http.MethodGet || req.Body() != nil || !strings.EqualFold(raw.URL.Host, b.host) || b.options.MaxBatchSize < 2 {
+		return req.Next()
+	}
+	item := &batchItem{req: req, done: make(chan struct{})}
+	b.mu.Lock()
+	if b.pending == nil {
+		bt := &batch{items: []*batchItem{item}, full: make(chan struct{})}
+		b.pending = bt
+		b.mu.Unlock()
+		return b.lead(bt, item)
+	}
+	bt := b.pending
+	bt.items = append(bt.items, item)
+	if len(bt.items) == b.options.MaxBatchSize {
+		b.pending = nil
+		close(bt.full)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-item.done:
+		if item.fallback {
+			return req.Next()
+		}
+		return item.resp, item.err
+	case <-raw.Context().Done():
+		// the batch request may still include this request; its response will be discarded
+		return nil, raw.Context().Err()
+	}
+}
+
+// lead waits for the batch window to elapse or the batch to fill, then sends the batch request.
+func (b *batchPolicy) lead(bt *batch, item *batchItem) (*http.Response, error) {
+	ctx := item.req.Raw().Context()
+	timer := time.NewTimer(b.options.Window)
+	select {
+	case <-timer.C:
+	case <-bt.full:
+	case <-ctx.Done():
+	}
+	timer.Stop()
+	b.mu.Lock()
+	if b.pending == bt {
+		b.pending = nil
+	}
+	items := bt.items
+	b.mu.Unlock()
+
+	if len(items) == 1 {
+		// nothing to batch with
+		return item.req.Next()
+	}
+
+	var resps map[string]*http.Response
+	err := ctx.Err()
+	if err == nil {
+		log.Writef(LogBatch, "sending %d requests in a batch request", len(items))
+		resps, err = b.send(item.req, items)
+	}
+	for i, it := range items {
+		if it == item {
+			continue
+		}
+		if err != nil && ctx.Err() != nil {
+			// this request's context was cancelled; the others can still succeed without it
+			it.fallback = true
+		} else if err != nil {
+			it.err = err
+		} else if it.resp = resps[strconv.Itoa(i)]; it.resp == nil {
+			it.err = fmt.Errorf("batch response doesn't contain a response for %s", it.req.Raw().URL.Path)
+		}
+		close(it.done)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp := resps["0"]; resp != nil {
+		return resp, nil
+	}
+	return nil, fmt.Errorf("batch response doesn't contain a response for %s", item.req.Raw().URL.Path)
+}
+
+type batchRequest struct {
+	Requests []batchRequestItem `json:"requests"`
+}
+
+type batchRequestItem struct {
+	HTTPMethod string            `json:"httpMethod"`
+	Name       string            `json:"name"`
+	URL        string            `json:"url"`
+	Headers    map[string]string `json:"headers,omitempty"`
+}
+
+// batchItemHeaders returns the headers of a request in a batch, such as its client request ID and
+// If-Match, so the service applies them to that request. The batch request's Authorization applies
+// to all its requests, which are authorized by the same policy.
+func batchItemHeaders(h http.Header) map[string]string {
+	var headers map[string]string
+	for k, v := range h {
+		if len(v) == 0 || strings.EqualFold(k, shared.HeaderAuthorization) {
+			continue
+		}
+		if headers == nil {
+			headers = map[string]string{}
+		}
+		headers[k] = strings.Join(v, ", ")
+	}
+	return headers
+}
+
+type batchResponse struct {
+	Responses []batchResponseItem `json:"responses"`
+}
+
+type batchResponseItem struct {
+	Name           string            `json:"name"`
+	HTTPStatusCode int               `json:"httpStatusCode"`
+	Headers        map[string]string `json:"headers"`
+	Content        json.RawMessage   `json:"content"`
+}
+
+// send sends the batch request for items with the remaining policies of req, which is the first request
+// in the batch, and returns the response of each item by its name, the item's index in items.
+func (b *batchPolicy) send(req *azpolicy.Request, items []*batchItem) (map[string]*http.Response, error) {
+	br := batchRequest{Requests: make([]batchRequestItem, len(items))}
+	for i, it := range items {
+		br.Requests[i] = batchRequestItem{
+			HTTPMethod: http.MethodGet,
+			Name:       strconv.Itoa(i),
+			URL:        it.req.Raw().URL.RequestURI(),
+			Headers:    batchItemHeaders(it.req.Raw().Header),
+		}
+	}
+	body, err := json.Marshal(br)
+	if err != nil {
+		return nil, err
+	}
+	ctx := req.Raw().Context()
+	batchReq := req.Clone(ctx)
+	batchReq.Raw().Method = http.MethodPost
+	batchReq.Raw().URL.Path = batchPath
+	batchReq.Raw().URL.RawPath = ""
+	batchReq.Raw().URL.RawQuery = url.Values{"api-version": []string{batchAPIVersion}}.Encode()
+	// conditions apply to the first request, whose entry in the batch has them, not the batch request
+	for _, h := range []string{"If-Match", "If-None-Match", "If-Modified-Since", "If-Unmodified-Since"} {
+		batchReq.Raw().Header.Del(h)
+	}
+	if err := batchReq.SetBody(exported.NopCloser(bytes.NewReader(body)), shared.ContentTypeAppJSON); err != nil {
+		return nil, err
+	}
+	resp, err := batchReq.Next()
+	// the service responds 202 when it needs more time, with a Location to poll for the responses
+	for err == nil && resp.StatusCode == http.StatusAccepted {
+		location := resp.Header.Get(shared.HeaderLocation)
+		if location == "" {
+			return nil, errors.New("batch response has status 202 but no Location header")
+		}
+		delay := shared.RetryAfter(resp)
+		if delay <= 0 {
+			delay = defaultBatchPollingDelay
+		}
+		if err = shared.Delay(ctx, delay); err != nil {
+			return nil, err
+		}
+		pollReq := req.Clone(ctx)
+		if pollReq.Raw().URL, err = url.Parse(location); err != nil {
+			return nil, err
+		}
+		resp, err = pollReq.Next()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		// give each request the batch request's error response
+		content, err := runtime.Payload(resp)
+		if err != nil {
+			return nil, err
+		}
+		resps := make(map[string]*http.Response, len(items))
+		for i, it := range items {
+			resps[strconv.Itoa(i)] = &http.Response{
+				StatusCode: resp.StatusCode,
+				Status:     resp.Status,
+				Header:     resp.Header.Clone(),
+				Body:       shared.NewNopClosingBytesReader(content),
+				Request:    it.req.Raw(),
+			}
+		}
+		return resps, nil
+	}
+	var result batchResponse
+	if err := runtime.UnmarshalAsJSON(resp, &result); err != nil {
+		return nil, err
+	}
+	resps := make(map[string]*http.Response, len(result.Responses))
+	for _, r := range result.Responses {
+		i, err := strconv.Atoi(r.Name)
+		if err != nil || i < 0 || i >= len(items) {
+			continue
+		}
+		header := http.Header{}
+		for k, v := range r.Headers {
+			header.Set(k, v)
+		}
+		content := []byte(r.Content)
+		if bytes.Equal(content, []byte("null")) {
+			content = nil
+		}
+		if len(content) > 0 && header.Get(shared.HeaderContentType) == "" {
+			header.Set(shared.HeaderContentType, shared.ContentTypeAppJSON)
+		}
+		resps[r.Name] = &http.Response{
+			StatusCode:    r.HTTPStatusCode,
+			Status:        fmt.Sprintf("%d %s", r.HTTPStatusCode, http.StatusText(r.HTTPStatusCode)),
+			Header:        header,
+			Body:          shared.NewNopClosingBytesReader(content),
+			ContentLength: int64(len(content)),
+			Request:       items[i].req.Raw(),
+		}
+	}
+	return resps, nil
+}
@@ -0,0 +1,278 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	armpolicy "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/log"
+	azpolicy "github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+const batchTestEndpoint = "https://management.azure.com"
+
+func newBatchTestPipeline(t *testing.T, batch armpolicy.BatchOptions, transport shared.TransportFunc) runtime.Pipeline {
+	pl, err := NewPipeline("armtest", "v1.2.3", mockCredential{}, runtime.PipelineOptions{}, &armpolicy.ClientOptions{
+		ClientOptions: azpolicy.ClientOptions{
+			Retry:     azpolicy.RetryOptions{MaxRetries: -1},
+			Transport: transport,
+		},
+		Batch:                 batch,
+		DisableRPRegistration: true,
+	})
+	require.NoError(t, err)
+	return pl
+}
+
+// batchContent returns a batch response for the requests in the batch request body, in reverse order
+func batchContent(t *testing.T, req *http.Request) []byte {
+	var br batchRequest
+	require.NoError(t, json.NewDecoder(req.Body).Decode(&br))
+	resp := batchResponse{}
+	for i := len(br.Requests) - 1; i >= 0; i-- {
+		r := br.Requests[i]
+		item := batchResponseItem{
+			Name:           r.Name,
+			HTTPStatusCode: http.StatusOK,
+			Headers:        map[string]string{"x-ms-ratelimit-remaining-subscription-reads": strconv.Itoa(100 - i)},
+			Content:        json.RawMessage(`{"id":"` + strings.Split(r.URL, "?")[0] + `"}`),
+		}
+		if strings.Contains(r.URL, "missing") {
+			item.HTTPStatusCode = http.StatusNotFound
+			item.Content = json.RawMessage(`{"error":{"code":"ResourceNotFound"}}`)
+		}
+		resp.Responses = append(resp.Responses, item)
+	}
+	b, err := json.Marshal(resp)
+	require.NoError(t, err)
+	return b
+}
+
+func sendBatchTestRequests(t *testing.T, pl runtime.Pipeline, paths []string) []*http.Response {
+	resps := make([]*http.Response, len(paths))
+	wg := sync.WaitGroup{}
+	for i, path := range paths {
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			req, err := runtime.NewRequest(context.Background(), http.MethodGet, batchTestEndpoint+path)
+			if err == nil {
+				resps[i], err = pl.Do(req)
+			}
+			if err != nil {
+				t.Error(err)
+			}
+		}(i, path)
+	}
+	wg.Wait()
+	return resps
+}
+
+func TestBatchPolicy(t *testing.T) {
+	// batch tests send requests concurrently; listeners set by other tests aren't safe for that
+	log.SetListener(nil)
+	defer log.SetListener(nil)
+	var calls int32
+	pl := newBatchTestPipeline(t, armpolicy.BatchOptions{Window: time.Minute, MaxBatchSize: 3}, func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		if req.Method != http.MethodPost || req.URL.Path != batchPath {
+			t.Errorf("unexpected request %s %s", req.Method, req.URL)
+		}
+		require.Equal(t, batchAPIVersion, req.URL.Query().Get("api-version"))
+		require.Equal(t, "Bearer ***", req.Header.Get(shared.HeaderAuthorization))
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(bytes.NewReader(batchContent(t, req)))}, nil
+	})
+	paths := []string{"/subscriptions/sub/resourceGroups/a", "/subscriptions/sub/resourceGroups/b", "/subscriptions/sub/resourceGroups/missing"}
+	resps := sendBatchTestRequests(t, pl, paths)
+	require.EqualValues(t, 1, calls)
+	for i, resp := range resps {
+		require.NotNil(t, resp)
+		require.Equal(t, paths[i], resp.Request.URL.Path)
+		require.NotEmpty(t, resp.Header.Get("x-ms-ratelimit-remaining-subscription-reads"))
+		body, err := runtime.Payload(resp)
+		require.NoError(t, err)
+		if strings.HasSuffix(paths[i], "missing") {
+			require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			require.Contains(t, string(body), "ResourceNotFound")
+			continue
+		}
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, `{"id":"`+paths[i]+`"}`, string(body))
+	}
+}
+
+func TestBatchPolicyRequestHeaders(t *testing.T) {
+	log.SetListener(nil)
+	defer log.SetListener(nil)
+	items := make(chan []batchRequestItem, 1)
+	pl := newBatchTestPipeline(t, armpolicy.BatchOptions{Window: time.Minute, MaxBatchSize: 2}, func(req *http.Request) (*http.Response, error) {
+		require.Empty(t, req.Header.Get("If-Match"))
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		var br batchRequest
+		require.NoError(t, json.Unmarshal(body, &br))
+		items <- br.Requests
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(bytes.NewReader(batchContent(t, req)))}, nil
+	})
+	paths := []string{"/subscriptions/sub/resourceGroups/a", "/subscriptions/sub/resourceGroups/b"}
+	wg := sync.WaitGroup{}
+	for _, path := range paths {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			req, err := runtime.NewRequest(context.Background(), http.MethodGet, batchTestEndpoint+path)
+			if err == nil {
+				req.Raw().Header.Set("If-Match", path)
+				req.Raw().Header.Set(shared.HeaderXMSClientRequestID, path)
+				_, err = pl.Do(req)
+			}
+			if err != nil {
+				t.Error(err)
+			}
+		}(path)
+	}
+	wg.Wait()
+	// each request in the batch carries its own headers, but not the batch request's authorization
+	for _, item := range <-items {
+		require.Equal(t, item.URL, item.Headers["If-Match"])
+		require.Equal(t, item.URL, item.Headers[http.CanonicalHeaderKey(shared.HeaderXMSClientRequestID)])
+		require.NotContains(t, item.Headers, shared.HeaderAuthorization)
+	}
+}
+
+func TestBatchPolicyAccepted(t *testing.T) {
+	log.SetListener(nil)
+	defer log.SetListener(nil)
+	var calls int32
+	pl := newBatchTestPipeline(t, armpolicy.BatchOptions{Window: time.Minute, MaxBatchSize: 2}, func(req *http.Request) (*http.Response, error) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			require.Equal(t, http.MethodPost, req.Method)
+			content := batchContent(t, req)
+			// remember the content for the polling request
+			header := http.Header{}
+			header.Set(shared.HeaderLocation, batchTestEndpoint+"/batch/result?content="+string(content))
+			header.Set(shared.HeaderRetryAfter, "0")
+			return &http.Response{StatusCode: http.StatusAccepted, Header: header, Body: http.NoBody}, nil
+		default:
+			require.Equal(t, http.MethodGet, req.Method)
+			require.Equal(t, "/batch/result", req.URL.Path)
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(req.URL.Query().Get("content")))}, nil
+		}
+	})
+	resps := sendBatchTestRequests(t, pl, []string{"/subscriptions/sub/resourceGroups/a", "/subscriptions/sub/resourceGroups/b"})
+	require.EqualValues(t, 2, calls)
+	for _, resp := range resps {
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+}
+
+func TestBatchPolicyBatchError(t *testing.T) {
+	log.SetListener(nil)
+	defer log.SetListener(nil)
+	pl := newBatchTestPipeline(t, armpolicy.BatchOptions{Window: time.Minute, MaxBatchSize: 2}, func(req *http.Request) (*http.Response, error) {
+		header := http.Header{}
+		header.Set("x-ms-ratelimit-remaining-subscription-reads", "0")
+		return &http.Response{StatusCode: http.StatusForbidden, Header: header, Body: io.NopCloser(strings.NewReader(`{"error":{"code":"AuthorizationFailed"}}`))}, nil
+	})
+	resps := sendBatchTestRequests(t, pl, []string{"/subscriptions/sub/resourceGroups/a", "/subscriptions/sub/resourceGroups/b"})
+	for _, resp := range resps {
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+		require.Equal(t, "0", resp.Header.Get("x-ms-ratelimit-remaining-subscription-reads"))
+		body, err := runtime.Payload(resp)
+		require.NoError(t, err)
+		require.Contains(t, string(body), "AuthorizationFailed")
+	}
+}
+
+func TestBatchPolicySingleRequest(t *testing.T) {
+	log.SetListener(nil)
+	defer log.SetListener(nil)
+	var calls int32
+	pl := newBatchTestPipeline(t, armpolicy.BatchOptions{Window: time.Millisecond}, func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/subscriptions/sub", req.URL.Path)
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+	resps := sendBatchTestRequests(t, pl, []string{"/subscriptions/sub"})
+	require.EqualValues(t, 1, calls)
+	require.Equal(t, http.StatusOK, resps[0].StatusCode)
+}
+
+func TestBatchPolicySkipsOtherRequests(t *testing.T) {
+	log.SetListener(nil)
+	defer log.SetListener(nil)
+	var calls int32
+	pl := newBatchTestPipeline(t, armpolicy.BatchOptions{Window: time.Minute}, func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		require.NotEqual(t, batchPath, req.URL.Path)
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+	for _, method := range []string{http.MethodPut, http.MethodDelete} {
+		req, err := runtime.NewRequest(context.Background(), method, batchTestEndpoint+"/subscriptions/sub")
+		require.NoError(t, err)
+		_, err = pl.Do(req)
+		require.NoError(t, err)
+	}
+	req, err := runtime.NewRequest(context.Background(), http.MethodGet, "https://contoso.com/subscriptions/sub")
+	require.NoError(t, err)
+	_, err = pl.Do(req)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, calls)
+}
+
+func TestBatchPolicyLeaderCanceled(t *testing.T) {
+	log.SetListener(nil)
+	defer log.SetListener(nil)
+	var calls int32
+	pl := newBatchTestPipeline(t, armpolicy.BatchOptions{Window: time.Minute, MaxBatchSize: 3}, func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		require.Equal(t, http.MethodGet, req.Method)
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, batchTestEndpoint+"/subscriptions/sub/resourceGroups/a")
+		if err == nil {
+			_, err = pl.Do(req)
+		}
+		leaderErr <- err
+	}()
+	// give the leader time to start the batch
+	time.Sleep(50 * time.Millisecond)
+	follower := make(chan *http.Response, 1)
+	go func() {
+		req, err := runtime.NewRequest(context.Background(), http.MethodGet, batchTestEndpoint+"/subscriptions/sub/resourceGroups/b")
+		require.NoError(t, err)
+		resp, err := pl.Do(req)
+		require.NoError(t, err)
+		follower <- resp
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-leaderErr, context.Canceled)
+	resp := <-follower
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.EqualValues(t, 1, calls)
+}
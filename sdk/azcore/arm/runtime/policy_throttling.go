@@ -0,0 +1,148 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	armpolicy "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	azpolicy "github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/log"
+)
+
+const (
+	// LogThrottling entries contain information specific to the throttling policy.
+	// Entries of this classification are written when the policy delays a request.
+	LogThrottling log.Event = "Throttling"
+)
+
+const defaultThrottlingMaxDelay = 10 * time.Second
+
+// ThrottlingPolicy tracks the request quota Azure Resource Manager reports remaining in each
+// subscription and the tenant, and delays requests when a quota is nearly exhausted.
+// Don't use this type directly, use NewThrottlingPolicy() instead.
+type ThrottlingPolicy struct {
+	options armpolicy.ThrottlingOptions
+	tracker *armpolicy.QuotaTracker
+}
+
+// NewThrottlingPolicy creates a policy object configured using the specified options.
+// Pass nil to accept the default values; this is the same as passing a zero-value options.
+func NewThrottlingPolicy(o *armpolicy.ThrottlingOptions) *ThrottlingPolicy {
+	if o == nil {
+		o = &armpolicy.ThrottlingOptions{}
+	}
+	p := &ThrottlingPolicy{
+		options: *o,
+		tracker: o.Tracker,
+	}
+	if p.tracker == nil {
+		p.tracker = armpolicy.NewQuotaTracker()
+	}
+	if p.options.MaxDelay <= 0 {
+		p.options.MaxDelay = defaultThrottlingMaxDelay
+	}
+	return p
+}
+
+// Quota returns the remaining quota most recently reported for the specified subscription.
+// See armpolicy.QuotaTracker.Quota for details.
+func (p *ThrottlingPolicy) Quota(subscriptionID string) (armpolicy.QuotaUpdate, bool) {
+	return p.tracker.Quota(subscriptionID)
+}
+
+// Do implements the azpolicy.Policy interface on ThrottlingPolicy.
+func (p *ThrottlingPolicy) Do(req *azpolicy.Request) (*http.Response, error) {
+	sub := subscriptionFromPath(req.Raw().URL.Path)
+	if delay := p.delay(sub, req.Raw().Method); delay > 0 {
+		log.Writef(LogThrottling, "delaying request to %s for %s", req.Raw().URL.Path, delay)
+		if err := shared.Delay(req.Raw().Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := req.Next()
+	if err != nil {
+		return resp, err
+	}
+	if update, ok := quotaUpdate(sub, resp); ok {
+		p.tracker.Update(update)
+		if p.options.OnUpdate != nil {
+			p.options.OnUpdate(update)
+		}
+	}
+	return resp, nil
+}
+
+// delay returns how long to delay a request with the specified method to the specified subscription.
+func (p *ThrottlingPolicy) delay(sub, method string) time.Duration {
+	if p.options.Threshold <= 0 {
+		return 0
+	}
+	var op string
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		op = "reads"
+	case http.MethodDelete:
+		op = "deletes"
+	default:
+		op = "writes"
+	}
+	q, ok := p.Quota(sub)
+	if !ok {
+		return 0
+	}
+	remaining := p.options.Threshold
+	for _, name := range []string{"subscription-" + op, "tenant-" + op} {
+		if v, ok := q.Remaining[name]; ok && v < remaining {
+			remaining = v
+		}
+	}
+	if remaining >= p.options.Threshold {
+		return 0
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	return time.Duration(int64(p.options.MaxDelay) * int64(p.options.Threshold-remaining) / int64(p.options.Threshold))
+}
+
+// quotaUpdate returns the remaining quota in resp's headers, if any.
+func quotaUpdate(sub string, resp *http.Response) (armpolicy.QuotaUpdate, bool) {
+	var remaining map[string]int
+	for k, v := range resp.Header {
+		k = strings.ToLower(k)
+		if !strings.HasPrefix(k, shared.HeaderRateLimitRemaining) || len(v) == 0 {
+			continue
+		}
+		n, err := strconv.Atoi(v[0])
+		if err != nil {
+			continue
+		}
+		if remaining == nil {
+			remaining = map[string]int{}
+		}
+		remaining[strings.TrimPrefix(k, shared.HeaderRateLimitRemaining)] = n
+	}
+	if remaining == nil {
+		return armpolicy.QuotaUpdate{}, false
+	}
+	return armpolicy.QuotaUpdate{SubscriptionID: sub, Remaining: remaining, Time: time.Now()}, true
+}
+
+// subscriptionFromPath returns the subscription ID in a request path such as
+// /subscriptions/{id}/resourceGroups/..., or the empty string for tenant-scoped paths.
+func subscriptionFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 1 && strings.EqualFold(segments[0], "subscriptions") {
+		return segments[1]
+	}
+	return ""
+}
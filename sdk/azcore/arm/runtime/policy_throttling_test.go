@@ -0,0 +1,173 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	armpolicy "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	azpolicy "github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+func newThrottlingTestPipeline(t *testing.T, o armpolicy.ThrottlingOptions, remaining map[string]string) runtime.Pipeline {
+	pl, err := NewPipeline("armtest", "v1.2.3", mockCredential{}, runtime.PipelineOptions{}, &armpolicy.ClientOptions{
+		ClientOptions: azpolicy.ClientOptions{
+			Retry: azpolicy.RetryOptions{MaxRetries: -1},
+			Transport: shared.TransportFunc(func(req *http.Request) (*http.Response, error) {
+				header := http.Header{}
+				for k, v := range remaining {
+					header.Set(k, v)
+				}
+				return &http.Response{StatusCode: http.StatusOK, Header: header, Body: http.NoBody, Request: req}, nil
+			}),
+		},
+		DisableRPRegistration: true,
+		Throttling:            o,
+	})
+	require.NoError(t, err)
+	return pl
+}
+
+func sendThrottlingTestRequest(t *testing.T, pl runtime.Pipeline, method, path string) {
+	req, err := runtime.NewRequest(context.Background(), method, "https://management.azure.com"+path)
+	require.NoError(t, err)
+	_, err = pl.Do(req)
+	require.NoError(t, err)
+}
+
+func TestThrottlingPolicyOnUpdate(t *testing.T) {
+	mu := sync.Mutex{}
+	updates := []armpolicy.QuotaUpdate{}
+	pl := newThrottlingTestPipeline(t, armpolicy.ThrottlingOptions{
+		OnUpdate: func(u armpolicy.QuotaUpdate) {
+			mu.Lock()
+			defer mu.Unlock()
+			updates = append(updates, u)
+		},
+	}, map[string]string{
+		"x-ms-ratelimit-remaining-subscription-reads": "11999",
+		"x-ms-ratelimit-remaining-tenant-reads":       "799",
+	})
+	sendThrottlingTestRequest(t, pl, http.MethodGet, "/subscriptions/sub/resourceGroups/rg")
+	sendThrottlingTestRequest(t, pl, http.MethodGet, "/providers/Microsoft.Compute/operations")
+	require.Len(t, updates, 2)
+	require.Equal(t, "sub", updates[0].SubscriptionID)
+	require.Equal(t, map[string]int{"subscription-reads": 11999, "tenant-reads": 799}, updates[0].Remaining)
+	require.False(t, updates[0].Time.IsZero())
+	require.Empty(t, updates[1].SubscriptionID)
+}
+
+func TestThrottlingPolicyQuota(t *testing.T) {
+	p := NewThrottlingPolicy(nil)
+	_, ok := p.Quota("sub")
+	require.False(t, ok)
+
+	now := time.Now()
+	p.tracker.Update(armpolicy.QuotaUpdate{SubscriptionID: "SUB", Remaining: map[string]int{"subscription-reads": 10, "tenant-reads": 100}, Time: now})
+	p.tracker.Update(armpolicy.QuotaUpdate{SubscriptionID: "sub", Remaining: map[string]int{"subscription-writes": 5}, Time: now})
+	p.tracker.Update(armpolicy.QuotaUpdate{SubscriptionID: "other", Remaining: map[string]int{"subscription-reads": 1}, Time: now})
+
+	q, ok := p.Quota("sub")
+	require.True(t, ok)
+	require.Equal(t, "sub", q.SubscriptionID)
+	require.Equal(t, map[string]int{"subscription-reads": 10, "subscription-writes": 5, "tenant-reads": 100}, q.Remaining)
+
+	q, ok = p.Quota("")
+	require.True(t, ok)
+	require.Equal(t, map[string]int{"tenant-reads": 100}, q.Remaining)
+
+	// subscriptions without quota of their own still have the tenant's
+	q, ok = p.Quota("unknown")
+	require.True(t, ok)
+	require.Equal(t, map[string]int{"tenant-reads": 100}, q.Remaining)
+
+	// the returned value is a copy
+	q.Remaining["tenant-reads"] = 0
+	q, _ = p.Quota("")
+	require.Equal(t, 100, q.Remaining["tenant-reads"])
+}
+
+func TestThrottlingPolicyDelay(t *testing.T) {
+	p := NewThrottlingPolicy(&armpolicy.ThrottlingOptions{Threshold: 100, MaxDelay: time.Second})
+	require.Zero(t, p.delay("sub", http.MethodGet))
+
+	p.tracker.Update(armpolicy.QuotaUpdate{SubscriptionID: "sub", Remaining: map[string]int{"subscription-reads": 150, "subscription-writes": 75, "subscription-deletes": 0}})
+	require.Zero(t, p.delay("sub", http.MethodGet))
+	require.Equal(t, 250*time.Millisecond, p.delay("sub", http.MethodPut))
+	require.Equal(t, time.Second, p.delay("sub", http.MethodDelete))
+	require.Zero(t, p.delay("other", http.MethodDelete))
+
+	// the lower of the subscription and tenant quotas applies
+	p.tracker.Update(armpolicy.QuotaUpdate{SubscriptionID: "sub", Remaining: map[string]int{"tenant-reads": 50}})
+	require.Equal(t, 500*time.Millisecond, p.delay("sub", http.MethodGet))
+	require.Equal(t, 500*time.Millisecond, p.delay("other", http.MethodGet))
+
+	// no delay without a threshold
+	p = NewThrottlingPolicy(nil)
+	p.tracker.Update(armpolicy.QuotaUpdate{SubscriptionID: "sub", Remaining: map[string]int{"subscription-reads": 0}})
+	require.Zero(t, p.delay("sub", http.MethodGet))
+}
+
+func TestThrottlingPolicyDelaysRequests(t *testing.T) {
+	pl := newThrottlingTestPipeline(t, armpolicy.ThrottlingOptions{Threshold: 10, MaxDelay: 100 * time.Millisecond}, map[string]string{
+		"x-ms-ratelimit-remaining-subscription-writes": "0",
+	})
+	start := time.Now()
+	sendThrottlingTestRequest(t, pl, http.MethodPut, "/subscriptions/sub/resourceGroups/rg")
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+	start = time.Now()
+	sendThrottlingTestRequest(t, pl, http.MethodPut, "/subscriptions/sub/resourceGroups/rg")
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	// reads have their own quota
+	start = time.Now()
+	sendThrottlingTestRequest(t, pl, http.MethodGet, "/subscriptions/sub/resourceGroups/rg")
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+
+	// the delay ends when the request's context does
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	req, err := runtime.NewRequest(ctx, http.MethodPut, "https://management.azure.com/subscriptions/sub/resourceGroups/rg")
+	require.NoError(t, err)
+	_, err = pl.Do(req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestThrottlingPolicySharedTracker(t *testing.T) {
+	tracker := armpolicy.NewQuotaTracker()
+	o := armpolicy.ThrottlingOptions{Threshold: 10, MaxDelay: 100 * time.Millisecond, Tracker: tracker}
+	pl1 := newThrottlingTestPipeline(t, o, map[string]string{"x-ms-ratelimit-remaining-subscription-writes": "0"})
+	pl2 := newThrottlingTestPipeline(t, o, nil)
+
+	sendThrottlingTestRequest(t, pl1, http.MethodPut, "/subscriptions/sub/resourceGroups/rg")
+	q, ok := tracker.Quota("sub")
+	require.True(t, ok)
+	require.Equal(t, map[string]int{"subscription-writes": 0}, q.Remaining)
+
+	// the other pipeline observes the quota reported to the first
+	start := time.Now()
+	sendThrottlingTestRequest(t, pl2, http.MethodPut, "/subscriptions/sub/resourceGroups/rg")
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestSubscriptionFromPath(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/subscriptions/sub/resourceGroups/rg": "sub",
+		"/Subscriptions/sub":                   "sub",
+		"/subscriptions":                       "",
+		"/providers/Microsoft.Compute":         "",
+		"/":                                    "",
+	} {
+		require.Equal(t, expected, subscriptionFromPath(path), path)
+	}
+}
@@ -6,26 +6,76 @@
 
 package cloud
 
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
 var (
 	// AzureChina contains configuration for Azure China.
 	AzureChina = Configuration{
-		ActiveDirectoryAuthorityHost: "https://login.chinacloudapi.cn/", Services: map[ServiceName]ServiceConfiguration{},
+		ActiveDirectoryAuthorityHost: "https://login.chinacloudapi.cn/",
+		Services: map[ServiceName]ServiceConfiguration{
+			ContainerRegistry: {Audience: "https://containerregistry.azure.net", Suffix: "azurecr.cn"},
+			CosmosDB:          {Suffix: "documents.azure.cn"},
+			EventHubs:         {Audience: "https://eventhubs.azure.net/", Suffix: "servicebus.chinacloudapi.cn"},
+			KeyVault:          {Audience: "https://vault.azure.cn", Suffix: "vault.azure.cn"},
+			Monitor:           {Audience: "https://monitor.azure.cn", Suffix: "monitor.azure.cn"},
+			ServiceBus:        {Audience: "https://servicebus.azure.net/", Suffix: "servicebus.chinacloudapi.cn"},
+			Storage:           {Audience: "https://storage.azure.com/", Suffix: "core.chinacloudapi.cn"},
+		},
 	}
 	// AzureGovernment contains configuration for Azure Government.
 	AzureGovernment = Configuration{
-		ActiveDirectoryAuthorityHost: "https://login.microsoftonline.us/", Services: map[ServiceName]ServiceConfiguration{},
+		ActiveDirectoryAuthorityHost: "https://login.microsoftonline.us/",
+		Services: map[ServiceName]ServiceConfiguration{
+			ContainerRegistry: {Audience: "https://containerregistry.azure.net", Suffix: "azurecr.us"},
+			CosmosDB:          {Suffix: "documents.azure.us"},
+			EventHubs:         {Audience: "https://eventhubs.azure.net/", Suffix: "servicebus.usgovcloudapi.net"},
+			KeyVault:          {Audience: "https://vault.usgovcloudapi.net", Suffix: "vault.usgovcloudapi.net"},
+			Monitor:           {Audience: "https://monitor.azure.us", Suffix: "monitor.azure.us"},
+			ServiceBus:        {Audience: "https://servicebus.azure.net/", Suffix: "servicebus.usgovcloudapi.net"},
+			Storage:           {Audience: "https://storage.azure.com/", Suffix: "core.usgovcloudapi.net"},
+		},
 	}
 	// AzurePublic contains configuration for Azure Public Cloud.
 	AzurePublic = Configuration{
-		ActiveDirectoryAuthorityHost: "https://login.microsoftonline.com/", Services: map[ServiceName]ServiceConfiguration{},
+		ActiveDirectoryAuthorityHost: "https://login.microsoftonline.com/",
+		Services: map[ServiceName]ServiceConfiguration{
+			ContainerRegistry: {Audience: "https://containerregistry.azure.net", Suffix: "azurecr.io"},
+			CosmosDB:          {Suffix: "documents.azure.com"},
+			EventHubs:         {Audience: "https://eventhubs.azure.net/", Suffix: "servicebus.windows.net"},
+			KeyVault:          {Audience: "https://vault.azure.net", Suffix: "vault.azure.net"},
+			Monitor:           {Audience: "https://monitor.azure.com", Suffix: "monitor.azure.com"},
+			ServiceBus:        {Audience: "https://servicebus.azure.net/", Suffix: "servicebus.windows.net"},
+			Storage:           {Audience: "https://storage.azure.com/", Suffix: "core.windows.net"},
+		},
 	}
 )
 
 // ServiceName identifies a cloud service.
 type ServiceName string
 
-// ResourceManager is a global constant identifying Azure Resource Manager.
-const ResourceManager ServiceName = "resourceManager"
+const (
+	// ContainerRegistry is a global constant identifying Azure Container Registry.
+	ContainerRegistry ServiceName = "containerRegistry"
+	// CosmosDB is a global constant identifying Azure Cosmos DB. Its Audience is empty because
+	// Cosmos DB tokens are specific to an account.
+	CosmosDB ServiceName = "cosmosDB"
+	// EventHubs is a global constant identifying Azure Event Hubs.
+	EventHubs ServiceName = "eventHubs"
+	// KeyVault is a global constant identifying Azure Key Vault.
+	KeyVault ServiceName = "keyVault"
+	// Monitor is a global constant identifying Azure Monitor.
+	Monitor ServiceName = "monitor"
+	// ResourceManager is a global constant identifying Azure Resource Manager.
+	ResourceManager ServiceName = "resourceManager"
+	// ServiceBus is a global constant identifying Azure Service Bus.
+	ServiceBus ServiceName = "serviceBus"
+	// Storage is a global constant identifying Azure Storage.
+	Storage ServiceName = "storage"
+)
 
 // ServiceConfiguration configures a specific cloud service such as Azure Resource Manager.
 type ServiceConfiguration struct {
@@ -33,6 +83,9 @@ type ServiceConfiguration struct {
 	Audience string
 	// Endpoint is the service's base URL.
 	Endpoint string
+	// Suffix is the DNS suffix of the service's endpoints, for example "vault.azure.net". It's
+	// set for services such as Key Vault whose endpoints are specific to a resource.
+	Suffix string
 }
 
 // Configuration configures a cloud.
@@ -42,3 +95,38 @@ type Configuration struct {
 	// Services contains configuration for the cloud's services.
 	Services map[ServiceName]ServiceConfiguration
 }
+
+// ValidateEndpoint returns an error when endpoint is a URL of the specified service in a cloud other than c, that is,
+// when its host is in the DNS domain of the service in AzureChina, AzureGovernment or AzurePublic but not in the Suffix
+// of c's configuration for the service. Hosts outside these domains, such as custom domains, private DNS names and
+// emulators, are always valid because their cloud can't be determined. ValidateEndpoint doesn't validate endpoints
+// when c has no Suffix for the service.
+func (c Configuration) ValidateEndpoint(service ServiceName, endpoint string) error {
+	conf, ok := c.Services[service]
+	if !ok || conf.Suffix == "" {
+		return nil
+	}
+	if !strings.Contains(endpoint, "://") {
+		// endpoints such as Service Bus namespaces may be host names
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	host := strings.ToLower(u.Hostname())
+	if inDomain(host, conf.Suffix) {
+		return nil
+	}
+	for _, other := range []Configuration{AzureChina, AzureGovernment, AzurePublic} {
+		if o, ok := other.Services[service]; ok && o.Suffix != "" && inDomain(host, o.Suffix) {
+			return fmt.Errorf("%s is a %s endpoint in another cloud. Endpoints in the configured cloud end with %s", endpoint, service, conf.Suffix)
+		}
+	}
+	return nil
+}
+
+// inDomain returns true when host is a subdomain of the DNS suffix.
+func inDomain(host, suffix string) bool {
+	return strings.HasSuffix(host, "."+strings.ToLower(strings.TrimPrefix(suffix, ".")))
+}
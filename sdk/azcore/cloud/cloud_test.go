@@ -0,0 +1,62 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cloud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEndpoint(t *testing.T) {
+	for _, test := range []struct {
+		cloud    Configuration
+		service  ServiceName
+		endpoint string
+		valid    bool
+	}{
+		{AzurePublic, Storage, "https://account.blob.core.windows.net/container", true},
+		{AzurePublic, Storage, "https://ACCOUNT.BLOB.CORE.WINDOWS.NET", true},
+		{AzurePublic, Storage, "https://account.blob.core.usgovcloudapi.net", false},
+		{AzureGovernment, Storage, "https://account.blob.core.usgovcloudapi.net", true},
+		{AzureChina, KeyVault, "https://vault.vault.azure.cn/", true},
+		{AzureChina, KeyVault, "https://vault.vault.azure.net/", false},
+		{AzurePublic, ServiceBus, "namespace.servicebus.windows.net", true},
+		{AzurePublic, ServiceBus, "namespace.servicebus.chinacloudapi.cn", false},
+		{AzurePublic, CosmosDB, "https://localhost:8081", true},
+		{AzurePublic, Storage, "http://127.0.0.1:10000/devstoreaccount1", true},
+		{AzurePublic, ResourceManager, "https://management.contoso.com", true},
+		{Configuration{}, Storage, "https://account.blob.contoso.com", true},
+		{AzurePublic, Storage, "https://account.blob.contoso.com", true},
+		{AzurePublic, Storage, "https://account.azureedge.net", true},
+		{AzurePublic, Storage, "http://azurite:10000/devstoreaccount1", true},
+		{AzureGovernment, KeyVault, "https://vault.privatelink.vaultcore.azure.net", true},
+		{Configuration{Services: map[ServiceName]ServiceConfiguration{Storage: {Suffix: "core.contoso.com"}}}, Storage, "https://account.blob.core.windows.net", false},
+	} {
+		t.Run(test.endpoint, func(t *testing.T) {
+			err := test.cloud.ValidateEndpoint(test.service, test.endpoint)
+			if test.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestWellKnownServices(t *testing.T) {
+	for _, c := range []Configuration{AzureChina, AzureGovernment, AzurePublic} {
+		for _, s := range []ServiceName{ContainerRegistry, CosmosDB, EventHubs, KeyVault, Monitor, ServiceBus, Storage} {
+			conf, ok := c.Services[s]
+			require.True(t, ok, "%s: missing %s", c.ActiveDirectoryAuthorityHost, s)
+			require.NotEmpty(t, conf.Suffix)
+			if s != CosmosDB {
+				require.NotEmpty(t, conf.Audience)
+			}
+		}
+	}
+}
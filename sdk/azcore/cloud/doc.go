@@ -49,5 +49,11 @@ appropriate values:
 		cred, &arm.ClientOptions{ClientOptions: opts},
 	)
 	handle(err)
+
+Alternatively, ConfigurationFromMetadata creates a Configuration from the metadata published by a cloud's
+Azure Resource Manager endpoint:
+
+	c, err := cloud.ConfigurationFromMetadata(ctx, "https://management.local.azurestack.external", nil)
+	handle(err)
 */
 package cloud
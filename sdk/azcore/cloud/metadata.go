@@ -0,0 +1,211 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMetadataAPIVersion    = "2022-09-01"
+	defaultMetadataCacheDuration = 24 * time.Hour
+)
+
+// MetadataOptions contains optional values for ConfigurationFromMetadata.
+type MetadataOptions struct {
+	// APIVersion is the API version of the metadata endpoint. Defaults to "2022-09-01".
+	// Azure Stack Hub may require an older version such as "2015-01-01".
+	APIVersion string
+
+	// CacheDuration is how long a fetched configuration is reused for the same endpoint.
+	// Pass zero to accept the default value (24h). Pass a negative value to disable caching.
+	CacheDuration time.Duration
+
+	// Fallback is returned when the metadata endpoint can't be reached and there's no cached configuration
+	// for it, for example when the application starts while the cloud is offline.
+	Fallback *Configuration
+
+	// Transport sends the metadata request. Defaults to http.DefaultClient.
+	Transport interface {
+		Do(*http.Request) (*http.Response, error)
+	}
+}
+
+// ConfigurationFromMetadata creates a Configuration for the cloud hosting the Azure Resource Manager endpoint
+// by fetching the cloud's metadata from endpoint/metadata/endpoints. The Configuration contains the cloud's
+// authority host and configuration for ResourceManager, KeyVault and Storage.
+//
+// Configurations are cached per endpoint and API version. When the metadata request fails, ConfigurationFromMetadata
+// returns the cached configuration even if it has expired, then options.Fallback. It returns an error only when
+// neither is available.
+//   - endpoint is the Azure Resource Manager endpoint, for example "https://management.local.azurestack.external"
+//   - options contains optional settings; pass nil to accept the default values
+func ConfigurationFromMetadata(ctx context.Context, endpoint string, options *MetadataOptions) (Configuration, error) {
+	o := MetadataOptions{}
+	if options != nil {
+		o = *options
+	}
+	if o.APIVersion == "" {
+		o.APIVersion = defaultMetadataAPIVersion
+	}
+	if o.CacheDuration == 0 {
+		o.CacheDuration = defaultMetadataCacheDuration
+	}
+	if o.Transport == nil {
+		o.Transport = http.DefaultClient
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	key := metadataCacheKey{endpoint: endpoint, apiVersion: o.APIVersion}
+
+	metadataCache.mu.Lock()
+	cached, ok := metadataCache.entries[key]
+	metadataCache.mu.Unlock()
+	if ok && o.CacheDuration > 0 && time.Now().Before(cached.expires) {
+		return cached.config.clone(), nil
+	}
+
+	c, err := fetchMetadata(ctx, endpoint, o)
+	if err != nil {
+		if ok {
+			return cached.config.clone(), nil
+		}
+		if o.Fallback != nil {
+			return o.Fallback.clone(), nil
+		}
+		return Configuration{}, err
+	}
+	if o.CacheDuration > 0 {
+		metadataCache.mu.Lock()
+		metadataCache.entries[key] = metadataCacheEntry{config: c, expires: time.Now().Add(o.CacheDuration)}
+		metadataCache.mu.Unlock()
+	}
+	return c.clone(), nil
+}
+
+type metadataCacheKey struct {
+	endpoint, apiVersion string
+}
+
+type metadataCacheEntry struct {
+	config  Configuration
+	expires time.Time
+}
+
+var metadataCache = struct {
+	mu      sync.Mutex
+	entries map[metadataCacheKey]metadataCacheEntry
+}{entries: map[metadataCacheKey]metadataCacheEntry{}}
+
+// cloudMetadata is the relevant content of a cloud's metadata. Older API versions return
+// the same content with different names, for example "loginEndpoint" at the top level.
+type cloudMetadata struct {
+	Authentication struct {
+		LoginEndpoint string   `json:"loginEndpoint"`
+		Audiences     []string `json:"audiences"`
+	} `json:"authentication"`
+	// LoginEndpoint is the top-level login endpoint of older API versions
+	LoginEndpoint   string `json:"loginEndpoint"`
+	ResourceManager string `json:"resourceManager"`
+	Suffixes        struct {
+		KeyVaultDNS string `json:"keyVaultDns"`
+		Storage     string `json:"storage"`
+	} `json:"suffixes"`
+}
+
+func fetchMetadata(ctx context.Context, endpoint string, o MetadataOptions) (Configuration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/metadata/endpoints?api-version="+o.APIVersion, nil)
+	if err != nil {
+		return Configuration{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := o.Transport.Do(req)
+	if err != nil {
+		return Configuration{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Configuration{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Configuration{}, fmt.Errorf("metadata request to %s failed with status %d: %s", endpoint, resp.StatusCode, string(body))
+	}
+
+	// newer API versions return an array of clouds, older ones return a single cloud
+	var clouds []cloudMetadata
+	if err := json.Unmarshal(body, &clouds); err != nil {
+		var md cloudMetadata
+		if err := json.Unmarshal(body, &md); err != nil {
+			return Configuration{}, fmt.Errorf("unmarshalling metadata from %s: %w", endpoint, err)
+		}
+		clouds = []cloudMetadata{md}
+	}
+	md, err := selectCloud(clouds, endpoint)
+	if err != nil {
+		return Configuration{}, err
+	}
+	if md.Authentication.LoginEndpoint == "" {
+		md.Authentication.LoginEndpoint = md.LoginEndpoint
+	}
+	if md.Authentication.LoginEndpoint == "" {
+		return Configuration{}, fmt.Errorf("metadata from %s doesn't specify a login endpoint", endpoint)
+	}
+
+	arm := ServiceConfiguration{Endpoint: endpoint}
+	if len(md.Authentication.Audiences) > 0 {
+		arm.Audience = md.Authentication.Audiences[0]
+	}
+	c := Configuration{
+		ActiveDirectoryAuthorityHost: strings.TrimSuffix(md.Authentication.LoginEndpoint, "/") + "/",
+		Services:                     map[ServiceName]ServiceConfiguration{ResourceManager: arm},
+	}
+	// an ADFS login endpoint such as Azure Stack Hub's includes the "adfs" tenant, which the authority host mustn't
+	if strings.HasSuffix(strings.ToLower(c.ActiveDirectoryAuthorityHost), "/adfs/") {
+		c.ActiveDirectoryAuthorityHost = c.ActiveDirectoryAuthorityHost[:len(c.ActiveDirectoryAuthorityHost)-len("adfs/")]
+	}
+	if s := strings.TrimPrefix(md.Suffixes.KeyVaultDNS, "."); s != "" {
+		c.Services[KeyVault] = ServiceConfiguration{Audience: "https://" + s, Suffix: s}
+	}
+	if s := strings.TrimPrefix(md.Suffixes.Storage, "."); s != "" {
+		c.Services[Storage] = ServiceConfiguration{Suffix: s}
+	}
+	return c, nil
+}
+
+// selectCloud returns the cloud whose Resource Manager endpoint is endpoint. When none matches,
+// it returns the only cloud, if there is one.
+func selectCloud(clouds []cloudMetadata, endpoint string) (cloudMetadata, error) {
+	for _, c := range clouds {
+		if strings.EqualFold(strings.TrimSuffix(c.ResourceManager, "/"), endpoint) {
+			return c, nil
+		}
+	}
+	if len(clouds) == 1 {
+		return clouds[0], nil
+	}
+	return cloudMetadata{}, errors.New("metadata from " + endpoint + " doesn't describe a cloud with that Resource Manager endpoint")
+}
+
+// clone returns a copy of c that doesn't share its Services map.
+func (c Configuration) clone() Configuration {
+	cp := Configuration{ActiveDirectoryAuthorityHost: c.ActiveDirectoryAuthorityHost}
+	if c.Services != nil {
+		cp.Services = make(map[ServiceName]ServiceConfiguration, len(c.Services))
+		for k, v := range c.Services {
+			cp.Services[k] = v
+		}
+	}
+	return cp
+}
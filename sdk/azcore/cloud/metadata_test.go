@@ -0,0 +1,128 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const metadataTemplate = `[
+	{
+		"authentication": {
+			"audiences": ["https://management.core.windows.net/", "https://management.azure.com/"],
+			"loginEndpoint": "https://login.microsoftonline.com",
+			"tenant": "common"
+		},
+		"name": "AzureCloud",
+		"resourceManager": "https://management.azure.com/",
+		"suffixes": { "keyVaultDns": "vault.azure.net", "storage": "core.windows.net" }
+	},
+	{
+		"authentication": {
+			"audiences": ["https://management.contoso.onmicrosoft.com/"],
+			"loginEndpoint": "https://login.contoso.com/"
+		},
+		"name": "Contoso",
+		"resourceManager": "%s/",
+		"suffixes": { "keyVaultDns": ".vault.contoso.com", "storage": "storage.contoso.com" }
+	}
+]`
+
+func TestConfigurationFromMetadata(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithBody([]byte(fmt.Sprintf(metadataTemplate, srv.URL()))))
+
+	c, err := ConfigurationFromMetadata(context.Background(), srv.URL()+"/", &MetadataOptions{Transport: srv})
+	require.NoError(t, err)
+	require.Equal(t, "https://login.contoso.com/", c.ActiveDirectoryAuthorityHost)
+	require.Equal(t, ServiceConfiguration{Audience: "https://management.contoso.onmicrosoft.com/", Endpoint: srv.URL()}, c.Services[ResourceManager])
+	require.Equal(t, ServiceConfiguration{Audience: "https://vault.contoso.com", Suffix: "vault.contoso.com"}, c.Services[KeyVault])
+	require.Equal(t, ServiceConfiguration{Suffix: "storage.contoso.com"}, c.Services[Storage])
+	require.Equal(t, 1, srv.Requests())
+
+	// the second call should return a copy of the cached configuration
+	c.Services[Storage] = ServiceConfiguration{}
+	cached, err := ConfigurationFromMetadata(context.Background(), srv.URL(), &MetadataOptions{Transport: srv})
+	require.NoError(t, err)
+	require.Equal(t, "storage.contoso.com", cached.Services[Storage].Suffix)
+	require.Equal(t, 1, srv.Requests())
+}
+
+func TestConfigurationFromMetadataAzureStack(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithBody([]byte(`{
+		"galleryEndpoint": "https://gallery.local.azurestack.external/",
+		"authentication": {
+			"loginEndpoint": "https://adfs.local.azurestack.external/adfs/",
+			"audiences": ["https://management.adfs.azurestack.local/1234"]
+		}
+	}`)))
+
+	c, err := ConfigurationFromMetadata(context.Background(), srv.URL(), &MetadataOptions{APIVersion: "2015-01-01", CacheDuration: -1, Transport: srv})
+	require.NoError(t, err)
+	require.Equal(t, "https://adfs.local.azurestack.external/", c.ActiveDirectoryAuthorityHost)
+	require.Equal(t, ServiceConfiguration{Audience: "https://management.adfs.azurestack.local/1234", Endpoint: srv.URL()}, c.Services[ResourceManager])
+	require.NotContains(t, c.Services, KeyVault)
+	require.NotContains(t, c.Services, Storage)
+}
+
+func TestConfigurationFromMetadataTopLevelLoginEndpoint(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithBody([]byte(`{
+		"galleryEndpoint": "https://gallery.azure.com/",
+		"loginEndpoint": "https://login.contoso.com",
+		"authentication": {
+			"audiences": ["https://management.contoso.com/"]
+		}
+	}`)))
+
+	c, err := ConfigurationFromMetadata(context.Background(), srv.URL(), &MetadataOptions{APIVersion: "2015-01-01", CacheDuration: -1, Transport: srv})
+	require.NoError(t, err)
+	require.Equal(t, "https://login.contoso.com/", c.ActiveDirectoryAuthorityHost)
+	require.Equal(t, ServiceConfiguration{Audience: "https://management.contoso.com/", Endpoint: srv.URL()}, c.Services[ResourceManager])
+}
+
+func TestConfigurationFromMetadataFallback(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithStatusCode(http.StatusServiceUnavailable))
+
+	_, err := ConfigurationFromMetadata(context.Background(), srv.URL(), &MetadataOptions{Transport: srv})
+	require.Error(t, err)
+
+	fallback := Configuration{ActiveDirectoryAuthorityHost: "https://login.contoso.com/"}
+	srv.AppendResponse(mock.WithStatusCode(http.StatusServiceUnavailable))
+	c, err := ConfigurationFromMetadata(context.Background(), srv.URL(), &MetadataOptions{Fallback: &fallback, Transport: srv})
+	require.NoError(t, err)
+	require.Equal(t, fallback, c)
+}
+
+func TestConfigurationFromMetadataStaleCache(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithBody([]byte(fmt.Sprintf(metadataTemplate, srv.URL()))))
+	srv.AppendResponse(mock.WithStatusCode(http.StatusServiceUnavailable))
+
+	opts := MetadataOptions{CacheDuration: time.Nanosecond, Transport: srv}
+	expected, err := ConfigurationFromMetadata(context.Background(), srv.URL(), &opts)
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	actual, err := ConfigurationFromMetadata(context.Background(), srv.URL(), &opts)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+	require.Equal(t, 2, srv.Requests())
+}
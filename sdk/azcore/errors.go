@@ -12,3 +12,8 @@ import "github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
 // the service returns a non-success HTTP status code.
 // Use errors.As() to access this type in the error chain.
 type ResponseError = exported.ResponseError
+
+// CircuitOpenError is returned when a request isn't sent because the circuit breaker
+// for its host is open. See policy.CircuitBreakerOptions for more information.
+// Use errors.As() to access this type in the error chain.
+type CircuitOpenError = exported.CircuitOpenError
@@ -0,0 +1,26 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package fake provides the building blocks for fake servers.
+// This includes fakes for authentication, API responses, and more.
+//
+// A fake server is a struct containing a field for each API to fake. Each field is a func
+// that returns the fake response for the API, and has a `fake` struct tag containing the
+// HTTP method and path template that the API's requests are matched against.
+//
+//	type VirtualMachinesServer struct {
+//		Get func(req *http.Request) (fake.Responder[armcompute.VirtualMachinesClientGetResponse], fake.ErrorResponder) `fake:"GET /subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Compute/virtualMachines/{vmName}"`
+//		NewListPager func(req *http.Request) fake.PagerResponder[armcompute.VirtualMachinesClientListResponse] `fake:"GET /subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Compute/virtualMachines"`
+//		BeginDelete func(req *http.Request) (fake.PollerResponder[armcompute.VirtualMachinesClientDeleteResponse], fake.ErrorResponder) `fake:"DELETE /subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Compute/virtualMachines/{vmName}"`
+//	}
+//
+// A ServerTransport created from an instance of the fake server is then used as the client's
+// policy.ClientOptions.Transport, and dispatches the client's requests to the matching funcs.
+// The values of path parameters are available to the funcs via PathValue.
+//
+// NOTE: fakes are for testing purposes only. Responses are serialized as JSON and don't
+// necessarily reflect the behavior of the real services.
+package fake
@@ -0,0 +1,400 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/pollers"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/errorinfo"
+)
+
+// TokenCredential is a fake credential that implements the azcore.TokenCredential interface.
+type TokenCredential struct {
+	err error
+}
+
+// SetError sets the specified error to be returned from GetToken().
+// Use this to simulate an error during authentication.
+func (t *TokenCredential) SetError(err error) {
+	t.err = err
+}
+
+// GetToken implements the azcore.TokenCredential for the TokenCredential type.
+func (t *TokenCredential) GetToken(ctx context.Context, opts exported.TokenRequestOptions) (exported.AccessToken, error) {
+	if t.err != nil {
+		return exported.AccessToken{}, t.err
+	}
+	return exported.AccessToken{Token: "fake_token", ExpiresOn: time.Now().Add(24 * time.Hour)}, nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Responder represents a scalar response.
+type Responder[T any] struct {
+	httpStatus int
+	resp       T
+	opts       SetResponseOptions
+}
+
+// SetResponseOptions contains the optional values for Responder[T].SetResponse.
+type SetResponseOptions struct {
+	// Header contains optional HTTP headers to include in the response.
+	Header http.Header
+}
+
+// SetResponse sets the specified value to be returned.
+//   - httpStatus is the HTTP status code to be returned
+//   - resp is the response to be returned
+//   - o contains optional values, pass nil to accept the defaults
+func (r *Responder[T]) SetResponse(httpStatus int, resp T, o *SetResponseOptions) {
+	r.httpStatus = httpStatus
+	r.resp = resp
+	if o != nil {
+		r.opts = *o
+	}
+}
+
+func (r *Responder[T]) isSet() bool {
+	return r.httpStatus != 0
+}
+
+func (r *Responder[T]) response(req *http.Request) (*http.Response, error) {
+	if !r.isSet() {
+		return nil, newNonRetriableError(fmt.Errorf("fake.Responder[%T] was not set", r.resp))
+	}
+	return newResponse(req, r.httpStatus, r.resp, r.opts.Header)
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// ErrorResponder represents a scalar error response.
+type ErrorResponder struct {
+	err error
+}
+
+// SetError sets the specified error to be returned. The retry policy doesn't retry it.
+// Use SetResponseError for returning an *azcore.ResponseError.
+func (e *ErrorResponder) SetError(err error) {
+	e.err = err
+}
+
+// SetResponseError sets an *azcore.ResponseError with the specified values to be returned.
+// The retry policy doesn't retry it, regardless of httpStatus.
+//   - httpStatus is the HTTP status code
+//   - errorCode is the value to be used as the ResponseError.Code field
+func (e *ErrorResponder) SetResponseError(httpStatus int, errorCode string) {
+	e.err = &responseError{httpStatus: httpStatus, errorCode: errorCode}
+}
+
+func (e *ErrorResponder) isSet() bool {
+	return e.err != nil
+}
+
+func (e *ErrorResponder) response(req *http.Request) (*http.Response, error) {
+	var respErr *responseError
+	if errors.As(e.err, &respErr) {
+		return nil, respErr.newError(req)
+	}
+	return nil, newNonRetriableError(e.err)
+}
+
+// responseError is the sentinel for an error returned as an HTTP response.
+type responseError struct {
+	httpStatus int
+	errorCode  string
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("fake response error %d %s", e.httpStatus, e.errorCode)
+}
+
+// newError returns an *azcore.ResponseError for a response to req. The error is non-retriable
+// because the retry policy would otherwise retry some status codes, consuming later fake responses.
+func (e *responseError) newError(req *http.Request) error {
+	resp, err := newResponse(req, e.httpStatus, map[string]any{
+		"error": map[string]any{
+			"code":    e.errorCode,
+			"message": "fake response error",
+		},
+	}, nil)
+	if err != nil {
+		return newNonRetriableError(err)
+	}
+	resp.Header.Set("x-ms-error-code", e.errorCode)
+	return newNonRetriableError(exported.NewResponseError(resp))
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// PagerResponder represents a sequence of paged responses.
+// Responses are returned in the order in which they were added.
+type PagerResponder[T any] struct {
+	pages []any
+}
+
+// AddPageOptions contains the optional values for PagerResponder[T].AddPage.
+type AddPageOptions struct {
+	// Header contains optional HTTP headers to include in the response.
+	Header http.Header
+}
+
+type fakeResponse struct {
+	httpStatus int
+	page       any
+	header     http.Header
+}
+
+// AddPage adds a page to the sequence of responses.
+//   - httpStatus is the HTTP status code to be returned
+//   - page is the response page to be added
+//   - o contains optional values, pass nil to accept the defaults
+func (p *PagerResponder[T]) AddPage(httpStatus int, page T, o *AddPageOptions) {
+	pp := fakeResponse{httpStatus: httpStatus, page: page}
+	if o != nil {
+		pp.header = o.Header
+	}
+	p.pages = append(p.pages, pp)
+}
+
+// AddError adds an error to the sequence of responses.
+// The error is returned from the call to runtime.Pager[T].NextPage() that fetches it. The retry policy doesn't retry it.
+func (p *PagerResponder[T]) AddError(err error) {
+	p.pages = append(p.pages, err)
+}
+
+// AddResponseError adds an *azcore.ResponseError to the sequence of responses.
+// The error is returned from the call to runtime.Pager[T].NextPage() that fetches it. The retry policy doesn't retry it.
+//   - httpStatus is the HTTP status code
+//   - errorCode is the value to be used as the ResponseError.Code field
+func (p *PagerResponder[T]) AddResponseError(httpStatus int, errorCode string) {
+	p.pages = append(p.pages, &responseError{httpStatus: httpStatus, errorCode: errorCode})
+}
+
+func (p *PagerResponder[T]) more() bool {
+	return len(p.pages) > 0
+}
+
+// nextPage returns the next response in the sequence.
+// If there are more responses, the value of nextLinkName in the page is set to nextLink.
+func (p *PagerResponder[T]) nextPage(req *http.Request, nextLinkName, nextLink string) (*http.Response, error) {
+	if !p.more() {
+		return nil, newNonRetriableError(fmt.Errorf("fake.PagerResponder[%s] has no pages", shared.TypeOfT[T]()))
+	}
+	next := p.pages[0]
+	p.pages = p.pages[1:]
+	switch pp := next.(type) {
+	case *responseError:
+		return nil, pp.newError(req)
+	case error:
+		return nil, newNonRetriableError(pp)
+	case fakeResponse:
+		if !p.more() {
+			return newResponse(req, pp.httpStatus, pp.page, pp.header)
+		}
+		// inject the link to the next page
+		body, err := json.Marshal(pp.page)
+		if err != nil {
+			return nil, err
+		}
+		var page map[string]any
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("fake.PagerResponder[%s] page is not a JSON object: %v", shared.TypeOfT[T](), err)
+		}
+		page[nextLinkName] = nextLink
+		return newResponse(req, pp.httpStatus, page, pp.header)
+	default:
+		panic(fmt.Sprintf("unhandled page type %T", next))
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// PollerResponder represents a sequence of responses for a long-running operation.
+// Any non-terminal responses are returned in the order in which they were added.
+// The terminal response is always the final response.
+type PollerResponder[T any] struct {
+	nonTermResps []any
+	termResp     *fakeResponse
+	termErr      *responseError
+}
+
+// AddNonTerminalResponseOptions contains the optional values for PollerResponder[T].AddNonTerminalResponse.
+type AddNonTerminalResponseOptions struct {
+	// Header contains optional HTTP headers to include in the response.
+	Header http.Header
+}
+
+// AddNonTerminalResponse adds a non-terminal response to the sequence of responses.
+// The first non-terminal response is returned for the request that starts the operation.
+// If no non-terminal responses were added, that request returns http.StatusOK.
+//   - httpStatus is the HTTP status code to be returned
+//   - o contains optional values, pass nil to accept the defaults
+func (p *PollerResponder[T]) AddNonTerminalResponse(httpStatus int, o *AddNonTerminalResponseOptions) {
+	pp := fakeResponse{httpStatus: httpStatus}
+	if o != nil {
+		pp.header = o.Header
+	}
+	p.nonTermResps = append(p.nonTermResps, pp)
+}
+
+// AddPollingError adds an error to the sequence of responses.
+// Use this to simulate an error during polling. The retry policy doesn't retry it.
+// NOTE: the first response in the sequence, the response to the request that starts the operation, can't be an error.
+func (p *PollerResponder[T]) AddPollingError(err error) {
+	p.nonTermResps = append(p.nonTermResps, err)
+}
+
+// SetTerminalResponseOptions contains the optional values for PollerResponder[T].SetTerminalResponse.
+type SetTerminalResponseOptions struct {
+	// Header contains optional HTTP headers to include in the response.
+	Header http.Header
+}
+
+// SetTerminalResponse sets the provided value as the successful, terminal response.
+//   - httpStatus is the HTTP status code to be returned
+//   - result is the result of the operation
+//   - o contains optional values, pass nil to accept the defaults
+func (p *PollerResponder[T]) SetTerminalResponse(httpStatus int, result T, o *SetTerminalResponseOptions) {
+	pp := &fakeResponse{httpStatus: httpStatus, page: result}
+	if o != nil {
+		pp.header = o.Header
+	}
+	p.termResp = pp
+	p.termErr = nil
+}
+
+// SetTerminalError sets an *azcore.ResponseError with the specified values as the failed, terminal response.
+// The error is returned from the poll that fetches it. The retry policy doesn't retry it.
+//   - httpStatus is the HTTP status code
+//   - errorCode is the value to be used as the ResponseError.Code field
+func (p *PollerResponder[T]) SetTerminalError(httpStatus int, errorCode string) {
+	p.termErr = &responseError{httpStatus: httpStatus, errorCode: errorCode}
+	p.termResp = nil
+}
+
+func (p *PollerResponder[T]) isSet() bool {
+	return p.termResp != nil || p.termErr != nil
+}
+
+// initialResponse returns the response for the request that starts the operation.
+func (p *PollerResponder[T]) initialResponse(req *http.Request, pollURL string) (*http.Response, error) {
+	if !p.isSet() {
+		return nil, newNonRetriableError(fmt.Errorf("fake.PollerResponder[%s] terminal response was not set", shared.TypeOfT[T]()))
+	}
+	httpStatus := http.StatusOK
+	var header http.Header
+	if len(p.nonTermResps) > 0 {
+		if pp, ok := p.nonTermResps[0].(fakeResponse); ok {
+			p.nonTermResps = p.nonTermResps[1:]
+			httpStatus = pp.httpStatus
+			header = pp.header
+		}
+	}
+	resp, err := newResponse(req, httpStatus, nil, header)
+	if err != nil {
+		return nil, err
+	}
+	resp.Header.Set(shared.HeaderFakePollerStatus, pollers.StatusInProgress)
+	resp.Header.Set(shared.HeaderFakePollerURL, pollURL)
+	return resp, nil
+}
+
+func (p *PollerResponder[T]) more() bool {
+	return len(p.nonTermResps) > 0 || p.isSet()
+}
+
+// nextResponse returns the next response in the sequence.
+func (p *PollerResponder[T]) nextResponse(req *http.Request) (*http.Response, error) {
+	if len(p.nonTermResps) > 0 {
+		next := p.nonTermResps[0]
+		p.nonTermResps = p.nonTermResps[1:]
+		if err, ok := next.(error); ok {
+			return nil, newNonRetriableError(err)
+		}
+		pp := next.(fakeResponse)
+		resp, err := newResponse(req, pp.httpStatus, nil, pp.header)
+		if err != nil {
+			return nil, err
+		}
+		resp.Header.Set(shared.HeaderFakePollerStatus, pollers.StatusInProgress)
+		return resp, nil
+	}
+
+	if p.termErr != nil {
+		err := p.termErr.newError(req)
+		p.termErr = nil
+		return nil, err
+	} else if p.termResp == nil {
+		return nil, newNonRetriableError(fmt.Errorf("fake.PollerResponder[%s] has no more responses", shared.TypeOfT[T]()))
+	}
+	resp, err := newResponse(req, p.termResp.httpStatus, p.termResp.page, p.termResp.header)
+	if err != nil {
+		return nil, err
+	}
+	p.termResp = nil
+	resp.Header.Set(shared.HeaderFakePollerStatus, pollers.StatusSucceeded)
+	return resp, nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// newResponse creates a response for req with the specified values.
+// A non-nil body is serialized as JSON.
+func newResponse(req *http.Request, httpStatus int, body any, header http.Header) (*http.Response, error) {
+	resp := &http.Response{
+		Body:       http.NoBody,
+		Header:     http.Header{},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Request:    req,
+		Status:     fmt.Sprintf("%d %s", httpStatus, http.StatusText(httpStatus)),
+		StatusCode: httpStatus,
+	}
+	for k, v := range header {
+		resp.Header[k] = append([]string(nil), v...)
+	}
+	if body != nil && httpStatus != http.StatusNoContent {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(content))
+		resp.ContentLength = int64(len(content))
+		resp.Header.Set(shared.HeaderContentType, shared.ContentTypeAppJSON)
+		resp.Header.Set(shared.HeaderContentLength, strconv.Itoa(len(content)))
+	}
+	return resp, nil
+}
+
+// nonRetriableError ensures the retry policy doesn't retry errors caused by a misconfigured fake.
+type nonRetriableError struct {
+	error
+}
+
+func newNonRetriableError(err error) error {
+	return &nonRetriableError{err}
+}
+
+func (e *nonRetriableError) Unwrap() error {
+	return e.error
+}
+
+func (*nonRetriableError) NonRetriable() {}
+
+var _ errorinfo.NonRetriable = (*nonRetriableError)(nil)
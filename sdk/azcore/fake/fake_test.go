@@ -0,0 +1,185 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fake
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/pollers"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/errorinfo"
+	"github.com/stretchr/testify/require"
+)
+
+type widget struct {
+	Name string `json:"name"`
+}
+
+type widgetPage struct {
+	NextLink *string  `json:"nextLink,omitempty"`
+	Value    []widget `json:"value"`
+}
+
+func newTestRequest(t *testing.T) *http.Request {
+	req, err := http.NewRequest(http.MethodGet, "https://contoso.com/widgets", nil)
+	require.NoError(t, err)
+	return req
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestTokenCredential(t *testing.T) {
+	cred := TokenCredential{}
+	tk, err := cred.GetToken(context.Background(), exported.TokenRequestOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, tk.Token)
+
+	myErr := errors.New("failed")
+	cred.SetError(myErr)
+	_, err = cred.GetToken(context.Background(), exported.TokenRequestOptions{})
+	require.ErrorIs(t, err, myErr)
+}
+
+func TestResponder(t *testing.T) {
+	req := newTestRequest(t)
+	r := Responder[widget]{}
+	_, err := r.response(req)
+	require.Error(t, err)
+
+	r.SetResponse(http.StatusOK, widget{Name: "foo"}, &SetResponseOptions{Header: http.Header{"Etag": []string{"etag"}}})
+	resp, err := r.response(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Same(t, req, resp.Request)
+	require.Equal(t, "etag", resp.Header.Get("ETag"))
+	require.Equal(t, shared.ContentTypeAppJSON, resp.Header.Get(shared.HeaderContentType))
+	require.JSONEq(t, `{"name":"foo"}`, readBody(t, resp))
+}
+
+func TestErrorResponder(t *testing.T) {
+	req := newTestRequest(t)
+	e := ErrorResponder{}
+	require.False(t, e.isSet())
+
+	myErr := errors.New("failed")
+	e.SetError(myErr)
+	require.True(t, e.isSet())
+	_, err := e.response(req)
+	require.ErrorIs(t, err, myErr)
+
+	var nre errorinfo.NonRetriable
+	require.ErrorAs(t, err, &nre)
+
+	e.SetResponseError(http.StatusNotFound, "NotFound")
+	_, err = e.response(req)
+	require.ErrorAs(t, err, &nre)
+	var respErr *exported.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, http.StatusNotFound, respErr.StatusCode)
+	require.Equal(t, "NotFound", respErr.ErrorCode)
+	require.NotNil(t, respErr.RawResponse)
+}
+
+func TestPagerResponder(t *testing.T) {
+	req := newTestRequest(t)
+	p := PagerResponder[widgetPage]{}
+	require.False(t, p.more())
+	_, err := p.nextPage(req, "nextLink", "https://contoso.com/next")
+	require.Error(t, err)
+
+	myErr := errors.New("failed")
+	p.AddPage(http.StatusOK, widgetPage{Value: []widget{{Name: "one"}}}, nil)
+	p.AddError(myErr)
+	p.AddResponseError(http.StatusTooManyRequests, "Throttled")
+	p.AddPage(http.StatusOK, widgetPage{Value: []widget{{Name: "two"}}}, nil)
+
+	resp, err := p.nextPage(req, "nextLink", "https://contoso.com/next")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"nextLink":"https://contoso.com/next","value":[{"name":"one"}]}`, readBody(t, resp))
+
+	_, err = p.nextPage(req, "nextLink", "https://contoso.com/next")
+	require.ErrorIs(t, err, myErr)
+	var nre errorinfo.NonRetriable
+	require.ErrorAs(t, err, &nre)
+
+	_, err = p.nextPage(req, "nextLink", "https://contoso.com/next")
+	require.ErrorAs(t, err, &nre)
+	var respErr *exported.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, http.StatusTooManyRequests, respErr.StatusCode)
+
+	require.True(t, p.more())
+	resp, err = p.nextPage(req, "nextLink", "https://contoso.com/next")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"value":[{"name":"two"}]}`, readBody(t, resp))
+	require.False(t, p.more())
+}
+
+func TestPollerResponder(t *testing.T) {
+	req := newTestRequest(t)
+	p := PollerResponder[widget]{}
+	_, err := p.initialResponse(req, "https://contoso.com/poll")
+	require.Error(t, err)
+
+	myErr := errors.New("failed")
+	p.AddNonTerminalResponse(http.StatusCreated, nil)
+	p.AddPollingError(myErr)
+	p.AddNonTerminalResponse(http.StatusOK, &AddNonTerminalResponseOptions{Header: http.Header{"Retry-After": []string{"1"}}})
+	p.SetTerminalResponse(http.StatusOK, widget{Name: "done"}, nil)
+
+	resp, err := p.initialResponse(req, "https://contoso.com/poll")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, pollers.StatusInProgress, resp.Header.Get(shared.HeaderFakePollerStatus))
+	require.Equal(t, "https://contoso.com/poll", resp.Header.Get(shared.HeaderFakePollerURL))
+
+	_, err = p.nextResponse(req)
+	require.ErrorIs(t, err, myErr)
+	var nre errorinfo.NonRetriable
+	require.ErrorAs(t, err, &nre)
+
+	resp, err = p.nextResponse(req)
+	require.NoError(t, err)
+	require.Equal(t, pollers.StatusInProgress, resp.Header.Get(shared.HeaderFakePollerStatus))
+	require.Equal(t, "1", resp.Header.Get("Retry-After"))
+
+	require.True(t, p.more())
+	resp, err = p.nextResponse(req)
+	require.NoError(t, err)
+	require.Equal(t, pollers.StatusSucceeded, resp.Header.Get(shared.HeaderFakePollerStatus))
+	require.JSONEq(t, `{"name":"done"}`, readBody(t, resp))
+	require.False(t, p.more())
+
+	_, err = p.nextResponse(req)
+	require.Error(t, err)
+}
+
+func TestPollerResponderTerminalError(t *testing.T) {
+	req := newTestRequest(t)
+	p := PollerResponder[widget]{}
+	p.SetTerminalError(http.StatusConflict, "Conflict")
+
+	resp, err := p.initialResponse(req, "https://contoso.com/poll")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = p.nextResponse(req)
+	var respErr *exported.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, http.StatusConflict, respErr.StatusCode)
+	require.Equal(t, "Conflict", respErr.ErrorCode)
+	require.False(t, p.more())
+}
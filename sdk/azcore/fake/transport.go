@@ -0,0 +1,271 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// paths of the URLs the ServerTransport creates for pagers and pollers
+	pagersPath  = "/.fake/pagers/"
+	pollersPath = "/.fake/pollers/"
+)
+
+// ServerTransportOptions contains the optional values for NewServerTransport.
+type ServerTransportOptions struct {
+	// NextLinkName is the name of the JSON field in a page that contains the link to the next page.
+	// The default value is "nextLink".
+	NextLinkName string
+}
+
+// ServerTransport is a policy.Transporter that dispatches requests to the funcs of a fake server.
+// Its methods are safe for concurrent use.
+type ServerTransport struct {
+	routes       []route
+	nextLinkName string
+
+	mu      sync.Mutex
+	lastID  int
+	pagers  map[string]pagerResponder
+	pollers map[string]pollerResponder
+}
+
+// NewServerTransport creates a new ServerTransport for the specified fake server.
+//   - srv is a struct, or pointer to a struct, containing the funcs that create the fake responses (see the package documentation)
+//   - options contains optional values, pass nil to accept the defaults
+//
+// An error is returned if a field with a fake struct tag has an unsupported type or malformed tag.
+func NewServerTransport(srv any, options *ServerTransportOptions) (*ServerTransport, error) {
+	if options == nil {
+		options = &ServerTransportOptions{}
+	}
+	st := &ServerTransport{
+		nextLinkName: options.NextLinkName,
+		pagers:       map[string]pagerResponder{},
+		pollers:      map[string]pollerResponder{},
+	}
+	if st.nextLinkName == "" {
+		st.nextLinkName = "nextLink"
+	}
+	v := reflect.ValueOf(srv)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("fake server must be a struct, not %T", srv)
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag, ok := field.Tag.Lookup("fake")
+		if !ok {
+			continue
+		}
+		r, err := newRoute(field, tag)
+		if err != nil {
+			return nil, err
+		}
+		r.handler = v.Field(i)
+		st.routes = append(st.routes, r)
+	}
+	return st, nil
+}
+
+// Do implements the policy.Transporter interface for ServerTransport.
+func (s *ServerTransport) Do(req *http.Request) (*http.Response, error) {
+	if strings.HasPrefix(req.URL.Path, pagersPath) {
+		return s.nextPage(req, strings.TrimPrefix(req.URL.Path, pagersPath))
+	} else if strings.HasPrefix(req.URL.Path, pollersPath) {
+		return s.nextPoll(req, strings.TrimPrefix(req.URL.Path, pollersPath))
+	}
+
+	segments := strings.Split(strings.Trim(req.URL.EscapedPath(), "/"), "/")
+	for _, r := range s.routes {
+		if params, ok := r.match(req.Method, segments); ok {
+			if r.handler.IsNil() {
+				return nil, newNonRetriableError(fmt.Errorf("fake for method %s was not set", r.name))
+			}
+			return s.dispatch(r, req.WithContext(context.WithValue(req.Context(), ctxPathParamsKey{}, params)))
+		}
+	}
+	return nil, newNonRetriableError(fmt.Errorf("fake server has no method for %s %s", req.Method, req.URL.Path))
+}
+
+// dispatch calls the route's handler and converts its result to a response.
+func (s *ServerTransport) dispatch(r route, req *http.Request) (*http.Response, error) {
+	results := r.handler.Call([]reflect.Value{reflect.ValueOf(req)})
+	// the results are copied so they can be used with pointer receivers
+	first := reflect.New(results[0].Type())
+	first.Elem().Set(results[0])
+	if len(results) == 2 {
+		errResp := results[1].Interface().(ErrorResponder)
+		if errResp.isSet() {
+			return errResp.response(req)
+		}
+	}
+
+	switch resp := first.Interface().(type) {
+	case scalarResponder:
+		return resp.response(req)
+	case pagerResponder:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		id := s.newID()
+		httpResp, err := resp.nextPage(req, s.nextLinkName, s.newURL(req, pagersPath, id))
+		if resp.more() {
+			s.pagers[id] = resp
+		}
+		return httpResp, err
+	case pollerResponder:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		id := s.newID()
+		httpResp, err := resp.initialResponse(req, s.newURL(req, pollersPath, id))
+		if err == nil {
+			s.pollers[id] = resp
+		}
+		return httpResp, err
+	default:
+		// NewServerTransport validates the handler types
+		panic(fmt.Sprintf("unhandled responder type %T", resp))
+	}
+}
+
+func (s *ServerTransport) nextPage(req *http.Request, id string) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pager, ok := s.pagers[id]
+	if !ok {
+		return nil, newNonRetriableError(fmt.Errorf("fake server has no pager with ID %s", id))
+	}
+	resp, err := pager.nextPage(req, s.nextLinkName, req.URL.String())
+	if !pager.more() {
+		delete(s.pagers, id)
+	}
+	return resp, err
+}
+
+func (s *ServerTransport) nextPoll(req *http.Request, id string) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	poller, ok := s.pollers[id]
+	if !ok {
+		return nil, newNonRetriableError(fmt.Errorf("fake server has no poller with ID %s", id))
+	}
+	resp, err := poller.nextResponse(req)
+	if !poller.more() {
+		delete(s.pollers, id)
+	}
+	return resp, err
+}
+
+// newID returns a new ID for a pager or poller. Callers must hold s.mu.
+func (s *ServerTransport) newID() string {
+	s.lastID++
+	return strconv.Itoa(s.lastID)
+}
+
+// newURL returns a URL on the same host as req with the specified path and ID.
+func (s *ServerTransport) newURL(req *http.Request, path, id string) string {
+	u := url.URL{Scheme: req.URL.Scheme, Host: req.URL.Host, Path: path + id}
+	return u.String()
+}
+
+// the interfaces implemented by the responders returned from the fake server's funcs
+type (
+	scalarResponder interface {
+		response(req *http.Request) (*http.Response, error)
+	}
+
+	pagerResponder interface {
+		more() bool
+		nextPage(req *http.Request, nextLinkName, nextLink string) (*http.Response, error)
+	}
+
+	pollerResponder interface {
+		more() bool
+		initialResponse(req *http.Request, pollURL string) (*http.Response, error)
+		nextResponse(req *http.Request) (*http.Response, error)
+	}
+)
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// route associates a method and path template with a fake server's func.
+type route struct {
+	name     string
+	method   string
+	segments []string
+	handler  reflect.Value
+}
+
+var (
+	typeOfRequest         = reflect.TypeOf((*http.Request)(nil))
+	typeOfErrorResponder  = reflect.TypeOf(ErrorResponder{})
+	typeOfScalarResponder = reflect.TypeOf((*scalarResponder)(nil)).Elem()
+	typeOfPagerResponder  = reflect.TypeOf((*pagerResponder)(nil)).Elem()
+	typeOfPollerResponder = reflect.TypeOf((*pollerResponder)(nil)).Elem()
+)
+
+func newRoute(field reflect.StructField, tag string) (route, error) {
+	r := route{name: field.Name}
+	ft := field.Type
+	if ft.Kind() != reflect.Func || ft.NumIn() != 1 || ft.In(0) != typeOfRequest {
+		return route{}, fmt.Errorf("field %s must be a func with a single *http.Request parameter", field.Name)
+	}
+	switch {
+	case ft.NumOut() == 2 && reflect.PointerTo(ft.Out(0)).Implements(typeOfScalarResponder) && ft.Out(1) == typeOfErrorResponder:
+	case ft.NumOut() == 2 && reflect.PointerTo(ft.Out(0)).Implements(typeOfPollerResponder) && ft.Out(1) == typeOfErrorResponder:
+	case ft.NumOut() == 1 && reflect.PointerTo(ft.Out(0)).Implements(typeOfPagerResponder):
+	default:
+		return route{}, fmt.Errorf("field %s has unsupported return types", field.Name)
+	}
+
+	method, path, ok := strings.Cut(tag, " ")
+	if !ok || method == "" || !strings.HasPrefix(path, "/") {
+		return route{}, fmt.Errorf(`malformed fake struct tag on field %s, the value must be in the format "METHOD /path/{param}"`, field.Name)
+	}
+	r.method = method
+	r.segments = strings.Split(strings.Trim(path, "/"), "/")
+	return r, nil
+}
+
+// match returns the values of the path parameters if the request matches the route.
+func (r route) match(method string, segments []string) (map[string]string, bool) {
+	if method != r.method || len(segments) != len(r.segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, segment := range r.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			value, err := url.PathUnescape(segments[i])
+			if err != nil {
+				return nil, false
+			}
+			params[segment[1:len(segment)-1]] = value
+		} else if !strings.EqualFold(segment, segments[i]) {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+type ctxPathParamsKey struct{}
+
+// PathValue returns the value of the named path parameter from the request passed to a fake server's func.
+// It returns the empty string if the path template doesn't contain the named parameter.
+func PathValue(req *http.Request, name string) string {
+	params, _ := req.Context().Value(ctxPathParamsKey{}).(map[string]string)
+	return params[name]
+}
@@ -0,0 +1,335 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+type widgetServer struct {
+	Get          func(req *http.Request) (Responder[widget], ErrorResponder)       `fake:"GET /widgets/{name}"`
+	NewListPager func(req *http.Request) PagerResponder[widgetPage]                `fake:"GET /widgets"`
+	BeginCreate  func(req *http.Request) (PollerResponder[widget], ErrorResponder) `fake:"PUT /widgets/{name}"`
+	Delete       func(req *http.Request) (Responder[struct{}], ErrorResponder)     `fake:"DELETE /widgets/{name}"`
+	NotAFake     func(req *http.Request) (Responder[widget], ErrorResponder)
+}
+
+// widgetClient is a minimal client in the style of generated clients
+type widgetClient struct {
+	pl runtime.Pipeline
+}
+
+func newWidgetClient(t *testing.T, srv *widgetServer) *widgetClient {
+	transport, err := NewServerTransport(srv, nil)
+	require.NoError(t, err)
+	return &widgetClient{pl: runtime.NewPipeline("fake", "v1.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Retry:     policy.RetryOptions{MaxRetries: -1},
+		Transport: transport,
+	})}
+}
+
+func (c *widgetClient) get(ctx context.Context, name string) (widget, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodGet, "https://contoso.com/widgets/"+name)
+	if err != nil {
+		return widget{}, err
+	}
+	resp, err := c.pl.Do(req)
+	if err != nil {
+		return widget{}, err
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return widget{}, runtime.NewResponseError(resp)
+	}
+	var w widget
+	err = runtime.UnmarshalAsJSON(resp, &w)
+	return w, err
+}
+
+func (c *widgetClient) newListPager() *runtime.Pager[widgetPage] {
+	return runtime.NewPager(runtime.PagingHandler[widgetPage]{
+		More: func(page widgetPage) bool {
+			return page.NextLink != nil && *page.NextLink != ""
+		},
+		Fetcher: func(ctx context.Context, page *widgetPage) (widgetPage, error) {
+			endpoint := "https://contoso.com/widgets"
+			if page != nil {
+				endpoint = *page.NextLink
+			}
+			req, err := runtime.NewRequest(ctx, http.MethodGet, endpoint)
+			if err != nil {
+				return widgetPage{}, err
+			}
+			resp, err := c.pl.Do(req)
+			if err != nil {
+				return widgetPage{}, err
+			}
+			if !runtime.HasStatusCode(resp, http.StatusOK) {
+				return widgetPage{}, runtime.NewResponseError(resp)
+			}
+			var wp widgetPage
+			err = runtime.UnmarshalAsJSON(resp, &wp)
+			return wp, err
+		},
+	})
+}
+
+func (c *widgetClient) beginCreate(ctx context.Context, name string) (*runtime.Poller[widget], error) {
+	req, err := runtime.NewRequest(ctx, http.MethodPut, "https://contoso.com/widgets/"+name)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.pl.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusCreated) {
+		return nil, runtime.NewResponseError(resp)
+	}
+	return runtime.NewPoller[widget](resp, c.pl, nil)
+}
+
+func TestNewServerTransport(t *testing.T) {
+	_, err := NewServerTransport(42, nil)
+	require.Error(t, err)
+
+	_, err = NewServerTransport(struct {
+		Get func(req *http.Request) Responder[widget] `fake:"GET /widgets/{name}"`
+	}{}, nil)
+	require.Error(t, err)
+
+	_, err = NewServerTransport(struct {
+		Get func() (Responder[widget], ErrorResponder) `fake:"GET /widgets/{name}"`
+	}{}, nil)
+	require.Error(t, err)
+
+	_, err = NewServerTransport(struct {
+		Get func(req *http.Request) (Responder[widget], ErrorResponder) `fake:"/widgets/{name}"`
+	}{}, nil)
+	require.Error(t, err)
+
+	st, err := NewServerTransport(widgetServer{}, nil)
+	require.NoError(t, err)
+	require.Len(t, st.routes, 4)
+}
+
+func TestServerTransportResponder(t *testing.T) {
+	srv := widgetServer{
+		Get: func(req *http.Request) (resp Responder[widget], errResp ErrorResponder) {
+			switch name := PathValue(req, "name"); name {
+			case "missing":
+				errResp.SetResponseError(http.StatusNotFound, "WidgetNotFound")
+			case "broken":
+				errResp.SetError(errors.New("connection reset"))
+			default:
+				resp.SetResponse(http.StatusOK, widget{Name: name}, nil)
+			}
+			return
+		},
+	}
+	client := newWidgetClient(t, &srv)
+
+	w, err := client.get(context.Background(), "my%20widget")
+	require.NoError(t, err)
+	require.Equal(t, "my widget", w.Name)
+
+	_, err = client.get(context.Background(), "missing")
+	var respErr *exported.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, http.StatusNotFound, respErr.StatusCode)
+	require.Equal(t, "WidgetNotFound", respErr.ErrorCode)
+
+	_, err = client.get(context.Background(), "broken")
+	require.EqualError(t, err, "connection reset")
+
+	// unmatched route
+	req, err := runtime.NewRequest(context.Background(), http.MethodGet, "https://contoso.com/gadgets")
+	require.NoError(t, err)
+	_, err = client.pl.Do(req)
+	require.Error(t, err)
+
+	// fake not set
+	req, err = runtime.NewRequest(context.Background(), http.MethodDelete, "https://contoso.com/widgets/foo")
+	require.NoError(t, err)
+	_, err = client.pl.Do(req)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Delete")
+}
+
+func TestServerTransportPager(t *testing.T) {
+	srv := widgetServer{
+		NewListPager: func(req *http.Request) (resp PagerResponder[widgetPage]) {
+			resp.AddPage(http.StatusOK, widgetPage{Value: []widget{{Name: "one"}, {Name: "two"}}}, nil)
+			resp.AddResponseError(http.StatusInternalServerError, "InternalError")
+			resp.AddPage(http.StatusOK, widgetPage{Value: []widget{{Name: "three"}}}, nil)
+			return
+		},
+	}
+	client := newWidgetClient(t, &srv)
+
+	pager := client.newListPager()
+	var names []string
+	var pageErrs int
+	for pager.More() {
+		page, err := pager.NextPage(context.Background())
+		if err != nil {
+			var respErr *exported.ResponseError
+			require.ErrorAs(t, err, &respErr)
+			require.Equal(t, "InternalError", respErr.ErrorCode)
+			pageErrs++
+			continue
+		}
+		for _, w := range page.Value {
+			names = append(names, w.Name)
+		}
+	}
+	require.Equal(t, []string{"one", "two", "three"}, names)
+	require.Equal(t, 1, pageErrs)
+}
+
+func TestServerTransportPoller(t *testing.T) {
+	srv := widgetServer{
+		BeginCreate: func(req *http.Request) (resp PollerResponder[widget], errResp ErrorResponder) {
+			if PathValue(req, "name") == "conflict" {
+				resp.SetTerminalError(http.StatusConflict, "WidgetConflict")
+				return
+			}
+			resp.AddNonTerminalResponse(http.StatusCreated, nil)
+			resp.AddNonTerminalResponse(http.StatusOK, nil)
+			resp.SetTerminalResponse(http.StatusOK, widget{Name: PathValue(req, "name")}, nil)
+			return
+		},
+	}
+	client := newWidgetClient(t, &srv)
+
+	poller, err := client.beginCreate(context.Background(), "foo")
+	require.NoError(t, err)
+	require.False(t, poller.Done())
+	w, err := poller.PollUntilDone(context.Background(), &runtime.PollUntilDoneOptions{Frequency: time.Millisecond})
+	require.NoError(t, err)
+	require.Equal(t, "foo", w.Name)
+
+	// resume the LRO from a token
+	poller, err = client.beginCreate(context.Background(), "bar")
+	require.NoError(t, err)
+	tk, err := poller.ResumeToken()
+	require.NoError(t, err)
+	poller, err = runtime.NewPollerFromResumeToken[widget](tk, client.pl, nil)
+	require.NoError(t, err)
+	w, err = poller.PollUntilDone(context.Background(), &runtime.PollUntilDoneOptions{Frequency: time.Millisecond})
+	require.NoError(t, err)
+	require.Equal(t, "bar", w.Name)
+
+	poller, err = client.beginCreate(context.Background(), "conflict")
+	require.NoError(t, err)
+	_, err = poller.PollUntilDone(context.Background(), &runtime.PollUntilDoneOptions{Frequency: time.Millisecond})
+	var respErr *exported.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, "WidgetConflict", respErr.ErrorCode)
+}
+
+func TestServerTransportDefaultRetryOptions(t *testing.T) {
+	calls := map[string]int{}
+	srv := widgetServer{
+		Get: func(req *http.Request) (resp Responder[widget], errResp ErrorResponder) {
+			name := PathValue(req, "name")
+			calls[name]++
+			if name == "unavailable" {
+				errResp.SetResponseError(http.StatusServiceUnavailable, "ServiceUnavailable")
+			} else {
+				errResp.SetError(errors.New("connection reset"))
+			}
+			return
+		},
+		NewListPager: func(req *http.Request) (resp PagerResponder[widgetPage]) {
+			resp.AddPage(http.StatusOK, widgetPage{Value: []widget{{Name: "one"}}}, nil)
+			resp.AddResponseError(http.StatusInternalServerError, "InternalError")
+			resp.AddPage(http.StatusOK, widgetPage{Value: []widget{{Name: "two"}}}, nil)
+			return
+		},
+		BeginCreate: func(req *http.Request) (resp PollerResponder[widget], errResp ErrorResponder) {
+			resp.AddNonTerminalResponse(http.StatusCreated, nil)
+			resp.AddPollingError(errors.New("connection reset"))
+			resp.SetTerminalError(http.StatusInternalServerError, "InternalError")
+			return
+		},
+	}
+	transport, err := NewServerTransport(&srv, nil)
+	require.NoError(t, err)
+	client := &widgetClient{pl: runtime.NewPipeline("fake", "v1.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Retry:     policy.RetryOptions{RetryDelay: time.Millisecond},
+		Transport: transport,
+	})}
+
+	// errors are returned as set rather than retried, which would call the handler again
+	_, err = client.get(context.Background(), "unavailable")
+	var respErr *exported.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, http.StatusServiceUnavailable, respErr.StatusCode)
+	require.Equal(t, 1, calls["unavailable"])
+	_, err = client.get(context.Background(), "broken")
+	require.EqualError(t, err, "connection reset")
+	require.Equal(t, 1, calls["broken"])
+
+	// a retry would consume the next page
+	pager := client.newListPager()
+	page, err := pager.NextPage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "one", page.Value[0].Name)
+	_, err = pager.NextPage(context.Background())
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, "InternalError", respErr.ErrorCode)
+	page, err = pager.NextPage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "two", page.Value[0].Name)
+
+	poller, err := client.beginCreate(context.Background(), "foo")
+	require.NoError(t, err)
+	_, err = poller.Poll(context.Background())
+	require.EqualError(t, err, "connection reset")
+	_, err = poller.Poll(context.Background())
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, http.StatusInternalServerError, respErr.StatusCode)
+	require.Equal(t, "InternalError", respErr.ErrorCode)
+}
+
+func TestServerTransportNextLinkName(t *testing.T) {
+	st, err := NewServerTransport(struct {
+		NewListPager func(req *http.Request) PagerResponder[map[string]any] `fake:"GET /widgets"`
+	}{
+		NewListPager: func(req *http.Request) (resp PagerResponder[map[string]any]) {
+			resp.AddPage(http.StatusOK, map[string]any{"value": []string{"one"}}, nil)
+			resp.AddPage(http.StatusOK, map[string]any{"value": []string{"two"}}, nil)
+			return
+		},
+	}, &ServerTransportOptions{NextLinkName: "@odata.nextLink"})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodGet, "https://contoso.com/widgets", nil)
+	require.NoError(t, err)
+	resp, err := st.Do(req)
+	require.NoError(t, err)
+	var page map[string]any
+	require.NoError(t, runtime.UnmarshalAsJSON(resp, &page))
+	require.Equal(t, "https://contoso.com/.fake/pagers/1", page["@odata.nextLink"])
+
+	req, err = http.NewRequest(http.MethodGet, page["@odata.nextLink"].(string), nil)
+	require.NoError(t, err)
+	resp, err = st.Do(req)
+	require.NoError(t, err)
+	page = nil
+	require.NoError(t, runtime.UnmarshalAsJSON(resp, &page))
+	require.NotContains(t, page, "@odata.nextLink")
+	require.Empty(t, st.pagers)
+}
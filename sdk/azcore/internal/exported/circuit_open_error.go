@@ -0,0 +1,32 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exported
+
+import (
+	"fmt"
+	"time"
+)
+
+// CircuitOpenError is returned when a request isn't sent because the circuit breaker for its host is open.
+// Exported as azcore.CircuitOpenError.
+type CircuitOpenError struct {
+	// Host is the host whose circuit is open.
+	Host string
+
+	// RetryAfter is when the circuit breaker will allow a trial request to the host.
+	RetryAfter time.Time
+}
+
+// Error implements the error interface for type CircuitOpenError.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker is open for host %s until %s", e.Host, e.RetryAfter.Format(time.RFC3339))
+}
+
+// NonRetriable indicates the request shouldn't be retried while the circuit is open.
+func (*CircuitOpenError) NonRetriable() {
+	// marker method
+}
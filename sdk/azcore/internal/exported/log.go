@@ -0,0 +1,116 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exported
+
+import (
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/log"
+)
+
+// LogLevel is the severity of a LogRecord. The values match those of log/slog.
+// Exported as log.Level.
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = -4
+	LogLevelInfo  LogLevel = 0
+	LogLevelWarn  LogLevel = 4
+	LogLevelError LogLevel = 8
+)
+
+// String implements the fmt.Stringer interface for LogLevel.
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// LogAttr is a key/value pair in a LogRecord.
+// Exported as log.Attr.
+type LogAttr struct {
+	Key   string
+	Value any
+}
+
+// LogRecord is a structured log entry.
+// Exported as log.Record.
+type LogRecord struct {
+	// Time is when the record was written.
+	Time time.Time
+
+	// Event is the event class of the record.
+	Event log.Event
+
+	// Level is the severity of the record.
+	Level LogLevel
+
+	// Message is a short description of the record.
+	Message string
+
+	// Attrs contains the record's data, e.g. the HTTP status code of a response.
+	Attrs []LogAttr
+}
+
+// recordLogger controls which events to write to the record listener.
+type recordLogger struct {
+	cls []log.Event
+	lst func(LogRecord)
+}
+
+// the process-wide record logger
+var recordLog recordLogger
+
+// SetLogRecordListener sets the listener for structured log records.
+// Exported as log.SetRecordListener.
+func SetLogRecordListener(lst func(LogRecord)) {
+	recordLog.lst = lst
+}
+
+// SetLogRecordEvents limits the log records written to those of the specified events.
+// Called by log.SetEvents.
+func SetLogRecordEvents(cls ...log.Event) {
+	recordLog.cls = cls
+}
+
+// ShouldLogRecord returns true if records of the specified event should be written.
+// If no record listener has been set this will return false.
+func ShouldLogRecord(cls log.Event) bool {
+	if recordLog.lst == nil {
+		return false
+	}
+	if len(recordLog.cls) == 0 {
+		return true
+	}
+	for _, c := range recordLog.cls {
+		if c == cls {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteLogRecord invokes the record listener with the specified record.
+// If the event shouldn't be logged or there is no listener then WriteLogRecord does nothing.
+func WriteLogRecord(r LogRecord) {
+	if !ShouldLogRecord(r.Event) {
+		return
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	recordLog.lst(r)
+}
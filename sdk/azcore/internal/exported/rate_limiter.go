@@ -0,0 +1,159 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exported
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+)
+
+// RateLimiter contains the token buckets limiting the rate of requests to each host.
+// Exported as policy.RateLimiter.
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets map[rateLimitKey]*TokenBucket
+}
+
+// rateLimitKey identifies a token bucket. Pipelines with different limits don't share buckets.
+type rateLimitKey struct {
+	host  string
+	rate  float64
+	burst int
+}
+
+// NewRateLimiter creates a RateLimiter with no token buckets.
+// Exported as policy.NewRateLimiter().
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{buckets: map[rateLimitKey]*TokenBucket{}}
+}
+
+// RateLimiterBucket returns r's token bucket for host with the specified limits, creating it as required.
+// It's a function instead of a method so it isn't part of policy.RateLimiter's API.
+func RateLimiterBucket(r *RateLimiter, host string, rate float64, burst int) *TokenBucket {
+	key := rateLimitKey{host: strings.ToLower(host), rate: rate, burst: burst}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.buckets[key]
+	if !ok {
+		b = &TokenBucket{
+			rate:   rate,
+			burst:  float64(burst),
+			tokens: float64(burst),
+			last:   time.Now(),
+		}
+		r.buckets[key] = b
+	}
+	return b
+}
+
+// TokenBucket limits the rate of requests to a host.
+// Requests take a token from the bucket, which is refilled at a constant rate.
+type TokenBucket struct {
+	mu    sync.Mutex
+	rate  float64
+	burst float64
+
+	// tokens is the number of tokens available as of last. It's negative when requests are waiting for tokens.
+	tokens float64
+	last   time.Time
+
+	// blockedUntil is the time before which no requests can be sent, as specified by Retry-After
+	blockedUntil time.Time
+}
+
+// refill adds the tokens accrued since the last refill. Must be called with the lock held.
+func (b *TokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// Take takes a token from the bucket, returning how long the caller must wait before sending its request.
+func (b *TokenBucket) Take(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	b.tokens--
+	var wait time.Duration
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	if blocked := b.blockedUntil.Sub(now); blocked > wait {
+		wait = blocked
+	}
+	return wait
+}
+
+// GiveBack returns a token when the caller didn't send its request.
+func (b *TokenBucket) GiveBack() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+// Update adapts the bucket to the rate limiting information in resp.
+func (b *TokenBucket) Update(resp *http.Response, now time.Time) {
+	remaining, hasRemaining := rateLimitRemaining(resp)
+	throttled := resp.StatusCode == http.StatusTooManyRequests
+	if !hasRemaining && !throttled {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	if hasRemaining && float64(remaining) < b.tokens {
+		// the service has less quota left than the bucket, don't exceed it
+		b.tokens = float64(remaining)
+	}
+	if throttled {
+		if b.tokens > 0 {
+			b.tokens = 0
+		}
+		if retryAfter := shared.RetryAfter(resp); retryAfter > 0 {
+			if until := now.Add(retryAfter); until.After(b.blockedUntil) {
+				b.blockedUntil = until
+			}
+		}
+	}
+}
+
+// rateLimitRemaining returns the smallest remaining quota in the x-ms-ratelimit-remaining-* headers.
+// Values can be a number or a comma-separated list of "policy;number" pairs, e.g.
+// x-ms-ratelimit-remaining-resource: Microsoft.Compute/HighCostGet3Min;107,Microsoft.Compute/HighCostGet30Min;587
+func rateLimitRemaining(resp *http.Response) (int, bool) {
+	remaining, found := 0, false
+	for k, values := range resp.Header {
+		if !strings.HasPrefix(strings.ToLower(k), shared.HeaderRateLimitRemaining) {
+			continue
+		}
+		for _, v := range values {
+			for _, part := range strings.Split(v, ",") {
+				if i := strings.LastIndex(part, ";"); i > -1 {
+					part = part[i+1:]
+				}
+				n, err := strconv.Atoi(strings.TrimSpace(part))
+				if err != nil {
+					continue
+				}
+				if !found || n < remaining {
+					remaining, found = n, true
+				}
+			}
+		}
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, found
+}
@@ -0,0 +1,90 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exported
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterBucket(t *testing.T) {
+	r := NewRateLimiter()
+	b := RateLimiterBucket(r, "contoso.com", 1, 2)
+	require.Same(t, b, RateLimiterBucket(r, "CONTOSO.com", 1, 2))
+	// hosts and limits have their own buckets
+	require.NotSame(t, b, RateLimiterBucket(r, "fabrikam.com", 1, 2))
+	require.NotSame(t, b, RateLimiterBucket(r, "contoso.com", 2, 2))
+	require.NotSame(t, b, RateLimiterBucket(r, "contoso.com", 1, 1))
+	require.NotSame(t, b, RateLimiterBucket(NewRateLimiter(), "contoso.com", 1, 2))
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := &TokenBucket{rate: 2, burst: 2, tokens: 2, last: now}
+	// burst
+	require.Zero(t, b.Take(now))
+	require.Zero(t, b.Take(now))
+	// then one request every 500ms
+	require.Equal(t, 500*time.Millisecond, b.Take(now))
+	require.Equal(t, time.Second, b.Take(now))
+	b.GiveBack()
+	require.Equal(t, time.Second, b.Take(now))
+	// the bucket refills up to the burst
+	now = now.Add(time.Hour)
+	require.Zero(t, b.Take(now))
+	require.Zero(t, b.Take(now))
+	require.Equal(t, 500*time.Millisecond, b.Take(now))
+}
+
+func TestTokenBucketUpdate(t *testing.T) {
+	now := time.Now()
+	b := &TokenBucket{rate: 1, burst: 10, tokens: 10, last: now}
+
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	b.Update(resp, now)
+	require.EqualValues(t, 10, b.tokens)
+
+	// the bucket shouldn't exceed the remaining quota
+	resp.Header.Set("x-ms-ratelimit-remaining-subscription-reads", "3")
+	resp.Header.Set("x-ms-ratelimit-remaining-resource", "Microsoft.Compute/HighCostGet3Min;2,Microsoft.Compute/HighCostGet30Min;587")
+	b.Update(resp, now)
+	require.EqualValues(t, 2, b.tokens)
+	require.Zero(t, b.Take(now))
+	require.Zero(t, b.Take(now))
+	require.Equal(t, time.Second, b.Take(now))
+
+	// throttling blocks requests for the duration of Retry-After
+	b = &TokenBucket{rate: 1, burst: 10, tokens: 10, last: now}
+	resp = &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set(shared.HeaderRetryAfter, "5")
+	b.Update(resp, now)
+	require.Equal(t, 5*time.Second, b.Take(now))
+	require.Equal(t, 5*time.Second, b.Take(now))
+	now = now.Add(5 * time.Second)
+	require.Equal(t, time.Duration(0), b.Take(now))
+}
+
+func TestRateLimitRemaining(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	_, ok := rateLimitRemaining(resp)
+	require.False(t, ok)
+
+	resp.Header.Set("x-ms-ratelimit-remaining-subscription-writes", "1199")
+	resp.Header.Set("x-ms-ratelimit-remaining-tenant-reads", "11999")
+	remaining, ok := rateLimitRemaining(resp)
+	require.True(t, ok)
+	require.Equal(t, 1199, remaining)
+
+	resp.Header.Set("x-ms-ratelimit-remaining-subscription-writes", "not a number")
+	remaining, ok = rateLimitRemaining(resp)
+	require.True(t, ok)
+	require.Equal(t, 11999, remaining)
+}
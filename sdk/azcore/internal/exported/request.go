@@ -169,3 +169,17 @@ func (req *Request) Clone(ctx context.Context) *Request {
 	r2.req = req.req.Clone(ctx)
 	return &r2
 }
+
+// IsolatedClone returns a deep copy of req with its context changed to ctx.
+// Unlike Request.Clone, the copy has its own operation values, so it can be sent
+// concurrently with req. Changes to the copy's operation values don't affect req.
+func IsolatedClone(req *Request, ctx context.Context) *Request {
+	r2 := req.Clone(ctx)
+	if req.values != nil {
+		r2.values = make(opValues, len(req.values))
+		for k, v := range req.values {
+			r2.values[k] = v
+		}
+	}
+	return r2
+}
@@ -171,6 +171,32 @@ func TestRequestClone(t *testing.T) {
 	}
 }
 
+func TestIsolatedClone(t *testing.T) {
+	type opValue struct {
+		Count int
+	}
+	req, err := NewRequest(context.Background(), http.MethodGet, testURL)
+	require.NoError(t, err)
+	req.SetOperationValue(opValue{Count: 1})
+
+	clone := IsolatedClone(req, context.Background())
+	var v opValue
+	require.True(t, clone.OperationValue(&v))
+	require.Equal(t, 1, v.Count)
+
+	// changing the clone's values doesn't affect the original
+	clone.SetOperationValue(opValue{Count: 2})
+	require.True(t, req.OperationValue(&v))
+	require.Equal(t, 1, v.Count)
+
+	// a clone of a request without values gets its own values
+	req, err = NewRequest(context.Background(), http.MethodGet, testURL)
+	require.NoError(t, err)
+	clone = IsolatedClone(req, context.Background())
+	clone.SetOperationValue(opValue{Count: 3})
+	require.False(t, req.OperationValue(&v))
+}
+
 func TestNewRequestFail(t *testing.T) {
 	req, err := NewRequest(context.Background(), http.MethodOptions, "://test.contoso.com/")
 	if err == nil {
@@ -8,17 +8,31 @@
 package log
 
 import (
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
 	azlog "github.com/Azure/azure-sdk-for-go/sdk/azcore/log"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/log"
 )
 
 type Event = log.Event
 
+type Attr = exported.LogAttr
+
+type Level = exported.LogLevel
+
+const (
+	EventRequest        = azlog.EventRequest
+	EventResponse       = azlog.EventResponse
+	EventRetryPolicy    = azlog.EventRetryPolicy
+	EventLRO            = azlog.EventLRO
+	EventCircuitBreaker = azlog.EventCircuitBreaker
+	EventHedging        = azlog.EventHedging
+)
+
 const (
-	EventRequest     = azlog.EventRequest
-	EventResponse    = azlog.EventResponse
-	EventRetryPolicy = azlog.EventRetryPolicy
-	EventLRO         = azlog.EventLRO
+	LevelDebug = azlog.LevelDebug
+	LevelInfo  = azlog.LevelInfo
+	LevelWarn  = azlog.LevelWarn
+	LevelError = azlog.LevelError
 )
 
 func Write(cls log.Event, msg string) {
@@ -36,3 +50,18 @@ func SetListener(lst func(Event, string)) {
 func Should(cls log.Event) bool {
 	return log.Should(cls)
 }
+
+// Record writes a structured record to the record listener.
+// Use ShouldRecord to avoid building attributes when no record will be written.
+func Record(cls log.Event, level Level, msg string, attrs ...Attr) {
+	exported.WriteLogRecord(exported.LogRecord{
+		Event:   cls,
+		Level:   level,
+		Message: msg,
+		Attrs:   attrs,
+	})
+}
+
+func ShouldRecord(cls log.Event) bool {
+	return exported.ShouldLogRecord(cls)
+}
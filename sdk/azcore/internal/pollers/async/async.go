@@ -54,6 +54,9 @@ type Poller[T any] struct {
 
 	// The LRO's current state.
 	CurState string `json:"state"`
+
+	// The LRO's percent complete, when reported by the status monitor.
+	percentComplete *float64
 }
 
 // New creates a new Poller from the provided initial response and final-state type.
@@ -103,14 +106,15 @@ func (p *Poller[T]) Poll(ctx context.Context) (*http.Response, error) {
 			p.resp = resp
 			return "", exported.NewResponseError(resp)
 		}
-		state, err := pollers.GetStatus(resp)
+		progress, err := pollers.GetProgress(resp)
 		if err != nil {
 			return "", err
-		} else if state == "" {
+		} else if progress.Status == "" {
 			return "", errors.New("the response did not contain a status")
 		}
 		p.resp = resp
-		p.CurState = state
+		p.CurState = progress.Status
+		p.percentComplete = progress.PercentComplete
 		return p.CurState, nil
 	})
 	if err != nil {
@@ -119,6 +123,11 @@ func (p *Poller[T]) Poll(ctx context.Context) (*http.Response, error) {
 	return p.resp, nil
 }
 
+// Progress returns the LRO's status and, when reported by the status monitor, its percent complete.
+func (p *Poller[T]) Progress() pollers.Progress {
+	return pollers.Progress{Status: p.CurState, PercentComplete: p.percentComplete}
+}
+
 func (p *Poller[T]) Result(ctx context.Context, out *T) error {
 	if p.resp.StatusCode == http.StatusNoContent {
 		return nil
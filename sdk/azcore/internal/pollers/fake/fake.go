@@ -0,0 +1,102 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/log"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/pollers"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+)
+
+// Kind is the identifier of this type in a resume token.
+const kind = "fake"
+
+// Applicable returns true if the LRO is a fake created by package azcore/fake.
+func Applicable(resp *http.Response) bool {
+	return resp.Header.Get(shared.HeaderFakePollerStatus) != ""
+}
+
+// CanResume returns true if the token can rehydrate this poller type.
+func CanResume(token map[string]interface{}) bool {
+	t, ok := token["type"]
+	if !ok {
+		return false
+	}
+	tt, ok := t.(string)
+	if !ok {
+		return false
+	}
+	return tt == kind
+}
+
+// Poller is an LRO poller for fake LROs.
+type Poller[T any] struct {
+	pl   exported.Pipeline
+	resp *http.Response
+
+	Type     string `json:"type"`
+	PollURL  string `json:"pollURL"`
+	CurState string `json:"state"`
+}
+
+// New creates a new Poller from the provided initial response.
+// Pass nil for response to create an empty Poller for rehydration.
+func New[T any](pl exported.Pipeline, resp *http.Response) (*Poller[T], error) {
+	if resp == nil {
+		log.Write(log.EventLRO, "Resuming fake poller.")
+		return &Poller[T]{pl: pl}, nil
+	}
+	log.Write(log.EventLRO, "Using fake poller.")
+	state := resp.Header.Get(shared.HeaderFakePollerStatus)
+	if state == "" {
+		return nil, errors.New("response is missing Fake-Poller-Status header")
+	}
+	pollURL := resp.Header.Get(shared.HeaderFakePollerURL)
+	if !pollers.IsValidURL(pollURL) {
+		return nil, fmt.Errorf("invalid polling URL %s", pollURL)
+	}
+	return &Poller[T]{
+		pl:       pl,
+		resp:     resp,
+		Type:     kind,
+		PollURL:  pollURL,
+		CurState: state,
+	}, nil
+}
+
+func (p *Poller[T]) Done() bool {
+	return pollers.IsTerminalState(p.CurState)
+}
+
+func (p *Poller[T]) Poll(ctx context.Context) (*http.Response, error) {
+	err := pollers.PollHelper(ctx, p.PollURL, p.pl, func(resp *http.Response) (string, error) {
+		state := resp.Header.Get(shared.HeaderFakePollerStatus)
+		if state == "" {
+			if !pollers.StatusCodeValid(resp) {
+				return "", exported.NewResponseError(resp)
+			}
+			return "", errors.New("response is missing Fake-Poller-Status header")
+		}
+		p.resp = resp
+		p.CurState = state
+		return p.CurState, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p.resp, nil
+}
+
+func (p *Poller[T]) Result(ctx context.Context, out *T) error {
+	return pollers.ResultHelper(p.resp, pollers.Failed(p.CurState), out)
+}
@@ -0,0 +1,145 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fake
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/pollers"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	"github.com/stretchr/testify/require"
+)
+
+const fakePollURL = "https://foo.bar.baz/fake/pollers/1"
+
+func initialResponse() *http.Response {
+	resp := &http.Response{
+		Header:     http.Header{},
+		StatusCode: http.StatusCreated,
+		Body:       http.NoBody,
+	}
+	resp.Header.Set(shared.HeaderFakePollerStatus, pollers.StatusInProgress)
+	resp.Header.Set(shared.HeaderFakePollerURL, fakePollURL)
+	return resp
+}
+
+func TestApplicable(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{},
+	}
+	require.False(t, Applicable(resp), "missing Fake-Poller-Status should not be applicable")
+	resp.Header.Set(shared.HeaderFakePollerStatus, pollers.StatusInProgress)
+	require.True(t, Applicable(resp), "having Fake-Poller-Status should be applicable")
+}
+
+func TestCanResume(t *testing.T) {
+	token := map[string]interface{}{}
+	require.False(t, CanResume(token))
+	token["type"] = kind
+	require.True(t, CanResume(token))
+	token["type"] = "something_else"
+	require.False(t, CanResume(token))
+	token["type"] = 123
+	require.False(t, CanResume(token))
+}
+
+func TestNew(t *testing.T) {
+	poller, err := New[struct{}](exported.Pipeline{}, nil)
+	require.NoError(t, err)
+	require.Empty(t, poller.CurState)
+
+	resp := initialResponse()
+	resp.Header.Del(shared.HeaderFakePollerStatus)
+	poller, err = New[struct{}](exported.Pipeline{}, resp)
+	require.Error(t, err)
+	require.Nil(t, poller)
+
+	resp = initialResponse()
+	resp.Header.Set(shared.HeaderFakePollerURL, "/no/host")
+	poller, err = New[struct{}](exported.Pipeline{}, resp)
+	require.Error(t, err)
+	require.Nil(t, poller)
+
+	poller, err = New[struct{}](exported.Pipeline{}, initialResponse())
+	require.NoError(t, err)
+	require.False(t, poller.Done())
+	require.Equal(t, fakePollURL, poller.PollURL)
+}
+
+type widget struct {
+	Size int `json:"size"`
+}
+
+func TestPollSucceeded(t *testing.T) {
+	pl := exported.NewPipeline(shared.TransportFunc(func(req *http.Request) (*http.Response, error) {
+		require.Equal(t, fakePollURL, req.URL.String())
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(`{ "size": 2 }`)),
+		}
+		resp.Header.Set(shared.HeaderFakePollerStatus, pollers.StatusSucceeded)
+		return resp, nil
+	}))
+	poller, err := New[widget](pl, initialResponse())
+	require.NoError(t, err)
+	_, err = poller.Poll(context.Background())
+	require.NoError(t, err)
+	require.True(t, poller.Done())
+	var result widget
+	require.NoError(t, poller.Result(context.Background(), &result))
+	require.Equal(t, 2, result.Size)
+}
+
+func TestPollFailed(t *testing.T) {
+	pl := exported.NewPipeline(shared.TransportFunc(func(req *http.Request) (*http.Response, error) {
+		resp := &http.Response{
+			StatusCode: http.StatusConflict,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(`{ "error": { "code": "Conflict" } }`)),
+		}
+		resp.Header.Set(shared.HeaderFakePollerStatus, pollers.StatusFailed)
+		return resp, nil
+	}))
+	poller, err := New[widget](pl, initialResponse())
+	require.NoError(t, err)
+	_, err = poller.Poll(context.Background())
+	require.NoError(t, err)
+	require.True(t, poller.Done())
+	var result widget
+	err = poller.Result(context.Background(), &result)
+	var respErr *exported.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, "Conflict", respErr.ErrorCode)
+}
+
+func TestPollMissingStatus(t *testing.T) {
+	statusCode := http.StatusOK
+	pl := exported.NewPipeline(shared.TransportFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: statusCode,
+			Header:     http.Header{},
+			Body:       http.NoBody,
+		}, nil
+	}))
+	poller, err := New[widget](pl, initialResponse())
+	require.NoError(t, err)
+	_, err = poller.Poll(context.Background())
+	require.Error(t, err)
+	require.False(t, poller.Done())
+
+	statusCode = http.StatusNotFound
+	_, err = poller.Poll(context.Background())
+	var respErr *exported.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.False(t, poller.Done())
+}
@@ -40,6 +40,8 @@ type Poller[T any] struct {
 	Method     string                `json:"method"`
 	FinalState pollers.FinalStateVia `json:"finalState"`
 	CurState   string                `json:"state"`
+
+	percentComplete *float64
 }
 
 // New creates a new Poller from the provided initial response.
@@ -95,14 +97,15 @@ func (p *Poller[T]) Poll(ctx context.Context) (*http.Response, error) {
 			p.resp = resp
 			return "", exported.NewResponseError(resp)
 		}
-		state, err := pollers.GetStatus(resp)
+		progress, err := pollers.GetProgress(resp)
 		if err != nil {
 			return "", err
-		} else if state == "" {
+		} else if progress.Status == "" {
 			return "", errors.New("the response did not contain a status")
 		}
 		p.resp = resp
-		p.CurState = state
+		p.CurState = progress.Status
+		p.percentComplete = progress.PercentComplete
 		return p.CurState, nil
 	})
 	if err != nil {
@@ -111,6 +114,11 @@ func (p *Poller[T]) Poll(ctx context.Context) (*http.Response, error) {
 	return p.resp, nil
 }
 
+// Progress returns the LRO's status and, when reported by the status monitor, its percent complete.
+func (p *Poller[T]) Progress() pollers.Progress {
+	return pollers.Progress{Status: p.CurState, PercentComplete: p.percentComplete}
+}
+
 func (p *Poller[T]) Result(ctx context.Context, out *T) error {
 	var req *exported.Request
 	var err error
@@ -22,3 +22,14 @@ const (
 	// FinalStateViaOpLocation indicates the final payload comes from the Operation-Location URL.
 	FinalStateViaOpLocation FinalStateVia = "operation-location"
 )
+
+// Progress is the progress of an LRO as reported by its status monitor.
+// Exported as runtime.PollProgress.
+type Progress struct {
+	// Status is the status of the LRO, e.g. "InProgress".
+	Status string
+
+	// PercentComplete is the percentage of the LRO that has completed.
+	// It's nil when the status monitor doesn't report it.
+	PercentComplete *float64
+}
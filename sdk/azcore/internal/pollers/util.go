@@ -196,6 +196,21 @@ func GetStatus(resp *http.Response) (string, error) {
 	return status(jsonBody), nil
 }
 
+// GetProgress returns the LRO's status and percent complete from the response body.
+// Typically used for Azure-AsyncOperation and Operation-Location flows.
+// If there is no status in the response body the status is the empty string.
+func GetProgress(resp *http.Response) (Progress, error) {
+	jsonBody, err := GetJSON(resp)
+	if err != nil {
+		return Progress{}, err
+	}
+	progress := Progress{Status: status(jsonBody)}
+	if pc, ok := jsonBody["percentComplete"].(float64); ok {
+		progress.PercentComplete = &pc
+	}
+	return progress, nil
+}
+
 // GetProvisioningState returns the LRO's state from the response body.
 // If there is no state in the response body the empty string is returned.
 func GetProvisioningState(resp *http.Response) (string, error) {
@@ -283,6 +298,9 @@ func PollHelper(ctx context.Context, endpoint string, pl exported.Pipeline, upda
 		return err
 	}
 	log.Writef(log.EventLRO, "State %s", state)
+	if log.ShouldRecord(log.EventLRO) {
+		log.Record(log.EventLRO, log.LevelInfo, "state", log.Attr{Key: "state", Value: state}, log.Attr{Key: "status_code", Value: resp.StatusCode})
+	}
 	return nil
 }
 
@@ -112,6 +112,28 @@ func TestGetStatusSuccess(t *testing.T) {
 	require.Equal(t, "InProgress", status)
 }
 
+func TestGetProgress(t *testing.T) {
+	resp := &http.Response{
+		Body: io.NopCloser(strings.NewReader(`{ "status": "InProgress", "percentComplete": 42.5 }`)),
+	}
+	progress, err := GetProgress(resp)
+	require.NoError(t, err)
+	require.Equal(t, "InProgress", progress.Status)
+	require.NotNil(t, progress.PercentComplete)
+	require.EqualValues(t, 42.5, *progress.PercentComplete)
+
+	resp = &http.Response{
+		Body: io.NopCloser(strings.NewReader(`{ "status": "Succeeded" }`)),
+	}
+	progress, err = GetProgress(resp)
+	require.NoError(t, err)
+	require.Equal(t, "Succeeded", progress.Status)
+	require.Nil(t, progress.PercentComplete)
+
+	_, err = GetProgress(&http.Response{Body: http.NoBody})
+	require.ErrorIs(t, err, ErrNoBody)
+}
+
 func TestGetNoBody(t *testing.T) {
 	resp := &http.Response{
 		Body: http.NoBody,
@@ -17,10 +17,15 @@ const (
 	HeaderAzureAsync             = "Azure-AsyncOperation"
 	HeaderContentLength          = "Content-Length"
 	HeaderContentType            = "Content-Type"
+	HeaderFakePollerStatus       = "Fake-Poller-Status"
+	HeaderFakePollerURL          = "Fake-Poller-URL"
 	HeaderLocation               = "Location"
 	HeaderOperationLocation      = "Operation-Location"
+	HeaderRateLimitRemaining     = "x-ms-ratelimit-remaining-" // prefix of headers such as x-ms-ratelimit-remaining-subscription-reads
 	HeaderRetryAfter             = "Retry-After"
 	HeaderUserAgent              = "User-Agent"
+	HeaderXMSClientRequestID     = "x-ms-client-request-id"
+	HeaderXMSRequestID           = "x-ms-request-id"
 )
 
 const BearerTokenPrefix = "Bearer "
@@ -30,5 +35,5 @@ const (
 	Module = "azcore"
 
 	// Version is the semantic version (see http://semver.org) of this module.
-	Version = "v1.3.2"
+	Version = "v1.5.0-beta.1"
 )
@@ -8,6 +8,7 @@
 package log
 
 import (
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/log"
 )
 
@@ -29,13 +30,23 @@ const (
 	// EventLRO entries contain information specific to long-running operations.
 	// This includes information like polling location, operation state, and sleep intervals.
 	EventLRO Event = "LongRunningOperation"
+
+	// EventCircuitBreaker entries contain information specific to the circuit breaker policy.
+	// This includes state transitions of a host's circuit.
+	EventCircuitBreaker Event = "CircuitBreaker"
+
+	// EventHedging entries contain information specific to the hedging policy.
+	// This includes when hedged requests are sent.
+	EventHedging Event = "Hedging"
 )
 
 // SetEvents is used to control which events are written to
 // the log.  By default all log events are writen.
+// It applies to both the listener and the record listener.
 // NOTE: this is not goroutine safe and should be called before using SDK clients.
 func SetEvents(cls ...Event) {
 	log.SetEvents(cls...)
+	exported.SetLogRecordEvents(cls...)
 }
 
 // SetListener will set the Logger to write to the specified Listener.
@@ -44,7 +55,41 @@ func SetListener(lst func(Event, string)) {
 	log.SetListener(lst)
 }
 
+// Level is the severity of a Record. The values match those of log/slog.
+type Level = exported.LogLevel
+
+const (
+	// LevelDebug is for records useful only when debugging, e.g. the start of each try of a request.
+	LevelDebug Level = exported.LogLevelDebug
+
+	// LevelInfo is for records of normal operation, e.g. a response was received.
+	LevelInfo Level = exported.LogLevelInfo
+
+	// LevelWarn is for records of transient problems, e.g. a request will be retried.
+	LevelWarn Level = exported.LogLevelWarn
+
+	// LevelError is for records of failures, e.g. a request couldn't be sent.
+	LevelError Level = exported.LogLevelError
+)
+
+// Attr is a key/value pair in a Record.
+type Attr = exported.LogAttr
+
+// Record is a structured log entry. Unlike the messages passed to the listener set by
+// SetListener, records contain typed attributes such as the request ID, HTTP status code,
+// duration and retry attempt of a request.
+type Record = exported.LogRecord
+
+// SetRecordListener will set the listener for structured records.
+// The listener set by SetListener continues to receive formatted messages.
+// See NewSlogListener for writing records to a log/slog.Handler.
+// NOTE: this is not goroutine safe and should be called before using SDK clients.
+func SetRecordListener(lst func(Record)) {
+	exported.SetLogRecordListener(lst)
+}
+
 // for testing purposes
 func resetEvents() {
 	log.TestResetEvents()
+	exported.SetLogRecordEvents()
 }
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/log"
 )
 
@@ -55,3 +56,38 @@ func TestLoggingEvent(t *testing.T) {
 		t.Fatalf("unexpected log entry: %s", testlog[EventRequest])
 	}
 }
+
+func TestRecordListener(t *testing.T) {
+	// ensure writing records with a nil listener doesn't fail
+	SetRecordListener(nil)
+	exported.WriteLogRecord(Record{Event: EventRequest, Message: "this should work just fine"})
+
+	var records []Record
+	SetRecordListener(func(r Record) {
+		records = append(records, r)
+	})
+	defer SetRecordListener(nil)
+	SetEvents(EventResponse)
+	defer resetEvents()
+
+	exported.WriteLogRecord(Record{Event: EventRequest, Message: "this shouldn't be in the log"})
+	exported.WriteLogRecord(Record{
+		Event:   EventResponse,
+		Level:   LevelWarn,
+		Message: "response received",
+		Attrs:   []Attr{{Key: "status_code", Value: http.StatusTooManyRequests}},
+	})
+	if l := len(records); l != 1 {
+		t.Fatalf("unexpected record count: %d", l)
+	}
+	r := records[0]
+	if r.Time.IsZero() {
+		t.Fatal("missing record time")
+	}
+	if r.Event != EventResponse || r.Level != LevelWarn || r.Message != "response received" {
+		t.Fatalf("unexpected record %v", r)
+	}
+	if len(r.Attrs) != 1 || r.Attrs[0].Key != "status_code" || r.Attrs[0].Value != http.StatusTooManyRequests {
+		t.Fatalf("unexpected attributes %v", r.Attrs)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+// NewSlogListener returns a record listener that writes records to h.
+// The event of each record is written as attribute "event".
+//
+//	log.SetRecordListener(log.NewSlogListener(slog.Default().Handler()))
+func NewSlogListener(h slog.Handler) func(Record) {
+	return func(r Record) {
+		ctx := context.Background()
+		level := slog.Level(r.Level)
+		if !h.Enabled(ctx, level) {
+			return
+		}
+		rec := slog.NewRecord(r.Time, level, r.Message, 0)
+		rec.AddAttrs(slog.String("event", string(r.Event)))
+		for _, a := range r.Attrs {
+			rec.AddAttrs(slog.Any(a.Key, a.Value))
+		}
+		// there's nothing useful to do with an error from the handler
+		_ = h.Handle(ctx, rec)
+	}
+}
@@ -0,0 +1,43 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSlogListener(t *testing.T) {
+	b := &bytes.Buffer{}
+	lst := NewSlogListener(slog.NewJSONHandler(b, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	lst(Record{Event: EventRetryPolicy, Level: LevelDebug, Message: "begin try"})
+	require.Zero(t, b.Len(), "the handler isn't enabled for debug records")
+
+	lst(Record{
+		Time:    time.Now(),
+		Event:   EventRetryPolicy,
+		Level:   LevelWarn,
+		Message: "retrying",
+		Attrs: []Attr{
+			{Key: "try", Value: int32(2)},
+			{Key: "delay", Value: time.Second},
+		},
+	})
+	entry := map[string]any{}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &entry))
+	require.Equal(t, "WARN", entry[slog.LevelKey])
+	require.Equal(t, "retrying", entry[slog.MessageKey])
+	require.Equal(t, string(EventRetryPolicy), entry["event"])
+	require.EqualValues(t, 2, entry["try"])
+	require.EqualValues(t, time.Second, entry["delay"])
+}
@@ -0,0 +1,159 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package metrics contains the definitions needed to support emitting metrics.
+package metrics
+
+import (
+	"context"
+)
+
+// ProviderOptions contains the optional values when creating a Provider.
+type ProviderOptions struct {
+	// for future expansion
+}
+
+// NewProvider creates a new Provider with the specified values.
+//   - newMeterFn is the underlying implementation for creating Meter instances
+//   - options contains optional values; pass nil to accept the default value
+func NewProvider(newMeterFn func(name, version string) Meter, options *ProviderOptions) Provider {
+	return Provider{
+		newMeterFn: newMeterFn,
+	}
+}
+
+// Provider is the factory that creates Meter instances.
+// It defaults to a no-op provider.
+type Provider struct {
+	newMeterFn func(name, version string) Meter
+}
+
+// NewMeter creates a new Meter for the specified name and version.
+//   - name - the name of the meter object, typically the name of the module containing the service client
+//   - version - the version of the module in which the service client resides
+func (p Provider) NewMeter(name, version string) (meter Meter) {
+	if p.newMeterFn != nil {
+		meter = p.newMeterFn(name, version)
+	}
+	return
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// MeterImpl abstracts the underlying implementation for Meter,
+// allowing it to work with various metrics implementations.
+// Any zero-values will have their default, no-op behavior.
+type MeterImpl struct {
+	// Int64Counter contains the implementation for the Meter.Int64Counter method.
+	Int64Counter func(name string, options *InstrumentOptions) Int64Counter
+
+	// Float64Histogram contains the implementation for the Meter.Float64Histogram method.
+	Float64Histogram func(name string, options *InstrumentOptions) Float64Histogram
+}
+
+// NewMeter creates a Meter with the specified implementation.
+func NewMeter(impl MeterImpl) Meter {
+	return Meter{
+		impl: impl,
+	}
+}
+
+// Meter is the factory that creates instruments.
+// A zero-value Meter provides a no-op implementation.
+type Meter struct {
+	impl MeterImpl
+}
+
+// Enabled returns true if this Meter is capable of creating instruments.
+func (m Meter) Enabled() bool {
+	return m.impl.Int64Counter != nil || m.impl.Float64Histogram != nil
+}
+
+// Int64Counter creates an instrument for recording increasing int64 values.
+//   - name identifies the instrument, e.g. "az.http.retries"
+//   - options contains optional values for the instrument, pass nil to accept any defaults
+func (m Meter) Int64Counter(name string, options *InstrumentOptions) Int64Counter {
+	if m.impl.Int64Counter != nil {
+		return m.impl.Int64Counter(name, options)
+	}
+	return Int64Counter{}
+}
+
+// Float64Histogram creates an instrument for recording the distribution of float64 values.
+//   - name identifies the instrument, e.g. "http.client.duration"
+//   - options contains optional values for the instrument, pass nil to accept any defaults
+func (m Meter) Float64Histogram(name string, options *InstrumentOptions) Float64Histogram {
+	if m.impl.Float64Histogram != nil {
+		return m.impl.Float64Histogram(name, options)
+	}
+	return Float64Histogram{}
+}
+
+// InstrumentOptions contains optional settings for creating an instrument.
+type InstrumentOptions struct {
+	// Description describes the instrument in human-readable terms.
+	Description string
+
+	// Unit is the unit of measurement of the instrument, e.g. "ms".
+	Unit string
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// NewInt64Counter creates an Int64Counter with the specified implementation.
+//   - addFn is the underlying implementation for the Int64Counter.Add method
+func NewInt64Counter(addFn func(ctx context.Context, incr int64, attrs ...Attribute)) Int64Counter {
+	return Int64Counter{
+		addFn: addFn,
+	}
+}
+
+// Int64Counter is an instrument that records increasing int64 values.
+// A zero-value Int64Counter provides a no-op implementation.
+type Int64Counter struct {
+	addFn func(ctx context.Context, incr int64, attrs ...Attribute)
+}
+
+// Add records the increment with an optional set of attributes.
+func (c Int64Counter) Add(ctx context.Context, incr int64, attrs ...Attribute) {
+	if c.addFn != nil {
+		c.addFn(ctx, incr, attrs...)
+	}
+}
+
+// NewFloat64Histogram creates a Float64Histogram with the specified implementation.
+//   - recordFn is the underlying implementation for the Float64Histogram.Record method
+func NewFloat64Histogram(recordFn func(ctx context.Context, value float64, attrs ...Attribute)) Float64Histogram {
+	return Float64Histogram{
+		recordFn: recordFn,
+	}
+}
+
+// Float64Histogram is an instrument that records the distribution of float64 values.
+// A zero-value Float64Histogram provides a no-op implementation.
+type Float64Histogram struct {
+	recordFn func(ctx context.Context, value float64, attrs ...Attribute)
+}
+
+// Record records the value with an optional set of attributes.
+func (h Float64Histogram) Record(ctx context.Context, value float64, attrs ...Attribute) {
+	if h.recordFn != nil {
+		h.recordFn(ctx, value, attrs...)
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Attribute is a key-value pair.
+type Attribute struct {
+	// Key is the name of the attribute.
+	Key string
+
+	// Value is the attribute's value.
+	// Types that are natively supported include int64, float64, int, bool, string.
+	// Any other type will be formatted per rules of fmt.Sprintf("%v").
+	Value any
+}
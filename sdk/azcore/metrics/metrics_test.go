@@ -0,0 +1,67 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderZeroValues(t *testing.T) {
+	pr := Provider{}
+	m := pr.NewMeter("name", "version")
+	require.Zero(t, m)
+	require.False(t, m.Enabled())
+	c := m.Int64Counter("counter", nil)
+	require.Zero(t, c)
+	c.Add(context.Background(), 1)
+	h := m.Float64Histogram("histogram", nil)
+	require.Zero(t, h)
+	h.Record(context.Background(), 1.5)
+}
+
+func TestProvider(t *testing.T) {
+	var counted int64
+	var recorded float64
+	var attributes []Attribute
+
+	pr := NewProvider(func(name, version string) Meter {
+		require.Equal(t, "name", name)
+		require.Equal(t, "version", version)
+		return NewMeter(MeterImpl{
+			Int64Counter: func(name string, options *InstrumentOptions) Int64Counter {
+				require.Equal(t, "counter", name)
+				return NewInt64Counter(func(ctx context.Context, incr int64, attrs ...Attribute) {
+					counted += incr
+					attributes = append(attributes, attrs...)
+				})
+			},
+			Float64Histogram: func(name string, options *InstrumentOptions) Float64Histogram {
+				require.Equal(t, "histogram", name)
+				require.Equal(t, "ms", options.Unit)
+				return NewFloat64Histogram(func(ctx context.Context, value float64, attrs ...Attribute) {
+					recorded = value
+					attributes = append(attributes, attrs...)
+				})
+			},
+		})
+	}, nil)
+	m := pr.NewMeter("name", "version")
+	require.True(t, m.Enabled())
+
+	c := m.Int64Counter("counter", nil)
+	c.Add(context.Background(), 2, Attribute{Key: "a", Value: 1})
+	c.Add(context.Background(), 3)
+	require.EqualValues(t, 5, counted)
+
+	h := m.Float64Histogram("histogram", &InstrumentOptions{Unit: "ms"})
+	h.Record(context.Background(), 1.5, Attribute{Key: "b", Value: "value"})
+	require.EqualValues(t, 1.5, recorded)
+	require.Equal(t, []Attribute{{Key: "a", Value: 1}, {Key: "b", Value: "value"}}, attributes)
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/metrics"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/tracing"
 )
 
@@ -32,12 +33,28 @@ type ClientOptions struct {
 	// APIVersion overrides the default version requested of the service. Set with caution as this package version has not been tested with arbitrary service versions.
 	APIVersion string
 
+	// CircuitBreaker configures the circuit breaker policy.
+	// It's disabled by default.
+	CircuitBreaker CircuitBreakerOptions
+
 	// Cloud specifies a cloud for the client. The default is Azure Public Cloud.
 	Cloud cloud.Configuration
 
+	// Hedging configures hedged requests for GET, HEAD and OPTIONS requests.
+	// It's disabled by default.
+	Hedging HedgingOptions
+
 	// Logging configures the built-in logging policy.
 	Logging LogOptions
 
+	// MetricsProvider configures the metrics provider.
+	// It defaults to a no-op meter.
+	MetricsProvider metrics.Provider
+
+	// RateLimit configures client-side rate limiting of requests.
+	// It's disabled by default.
+	RateLimit RateLimitOptions
+
 	// Retry configures the built-in retry policy.
 	Retry RetryOptions
 
@@ -60,6 +77,46 @@ type ClientOptions struct {
 	PerRetryPolicies []Policy
 }
 
+// CircuitBreakerOptions configures the circuit breaker policy's behavior.
+// The policy tracks the health of each host a client sends requests to. A host's circuit opens after
+// FailureThreshold consecutive failed tries, and requests to the host fail with *azcore.CircuitOpenError
+// without being sent. After OpenDuration, the circuit is half-open and allows one trial request. The
+// circuit closes when the trial succeeds and opens again when it fails.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failed tries that opens a host's circuit.
+	// The default value of zero disables the circuit breaker.
+	FailureThreshold int
+
+	// OpenDuration is how long a circuit stays open before allowing a trial request.
+	// The default value is 30 seconds.
+	OpenDuration time.Duration
+
+	// StatusCodes specifies the HTTP status codes that indicate a failed try. Transport errors
+	// also indicate a failed try. A nil slice will use the following values.
+	//   http.StatusInternalServerError 500
+	//   http.StatusBadGateway          502
+	//   http.StatusServiceUnavailable  503
+	//   http.StatusGatewayTimeout      504
+	// Specifying values will replace the default values.
+	StatusCodes []int
+}
+
+// HedgingOptions configures the hedging policy's behavior.
+// When a response to a GET, HEAD or OPTIONS request doesn't arrive within the hedging delay, the policy
+// sends the request again and returns whichever response arrives first. The other request is cancelled.
+// Hedging reduces tail latency at the cost of sending more requests.
+type HedgingOptions struct {
+	// Delay is how long to wait for a response before sending the hedged request.
+	// When Percentile is set, Delay is used until enough responses have been received to compute the percentile.
+	// Hedging is disabled when Delay and Percentile are both zero, the default.
+	Delay time.Duration
+
+	// Percentile specifies the delay as a percentile of the latency of recent responses. For example, a value
+	// of 95 sends the hedged request when a response takes longer than 95% of recent responses.
+	// Valid values are greater than 0 and less than 100.
+	Percentile float64
+}
+
 // LogOptions configures the logging policy's behavior.
 type LogOptions struct {
 	// IncludeBody indicates if request and response bodies should be included in logging.
@@ -115,6 +172,34 @@ type RetryOptions struct {
 	StatusCodes []int
 }
 
+// RateLimitOptions configures the rate limiting policy's behavior.
+// The policy limits the rate of requests sent to each host with a token bucket. It slows down when
+// responses report a low remaining quota in x-ms-ratelimit-remaining-* headers, and stops sending
+// requests to a host for the duration specified by the Retry-After header of a throttled response.
+// Each client has its own limits unless clients share a Limiter.
+type RateLimitOptions struct {
+	// RequestsPerSecond is the sustained rate of requests to send to each host.
+	// The default value of zero disables rate limiting.
+	RequestsPerSecond float64
+
+	// Burst is the maximum number of requests that can be sent to a host at once.
+	// The default value is RequestsPerSecond rounded up, with a minimum of one.
+	Burst int
+
+	// Limiter stores the state of the limits. Clients with the same Limiter, RequestsPerSecond and Burst
+	// share their limits, for example clients that share a Transport. When nil, each client has its own limits.
+	Limiter *RateLimiter
+}
+
+// RateLimiter stores the state of client-side rate limits so clients can share them.
+// It's safe for concurrent use. Don't use this type directly, use NewRateLimiter() instead.
+type RateLimiter = exported.RateLimiter
+
+// NewRateLimiter creates a RateLimiter to share between clients with RateLimitOptions.Limiter.
+func NewRateLimiter() *RateLimiter {
+	return exported.NewRateLimiter()
+}
+
 // TelemetryOptions configures the telemetry policy's behavior.
 type TelemetryOptions struct {
 	// ApplicationID is an application-specific identification string to add to the User-Agent.
@@ -134,6 +219,18 @@ type BearerTokenOptions struct {
 	// When this field isn't set, the policy follows its default behavior of authorizing every request with a bearer token from
 	// its given credential.
 	AuthorizationHandler AuthorizationHandler
+
+	// ProactiveRefresh refreshes tokens in the background before they expire, so requests don't wait for
+	// new tokens. A token is refreshed only when requests used it since it was acquired.
+	// It's disabled by default.
+	ProactiveRefresh bool
+
+	// SharedTokenCache shares tokens with the other BearerTokenPolicy instances in the process that set
+	// SharedTokenCache and have the same credential and scopes, so clients don't acquire a token each.
+	// Tokens are shared only when the credential is a pointer, such as *azidentity.DefaultAzureCredential.
+	// When it isn't, this setting has no effect and the policy caches its own tokens. Shared tokens
+	// that no policy used for an hour are deleted. It's disabled by default.
+	SharedTokenCache bool
 }
 
 // AuthorizationHandler allows SDK developers to insert custom logic that runs when BearerTokenPolicy must authorize a request.
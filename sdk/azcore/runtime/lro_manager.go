@@ -0,0 +1,263 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/log"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+)
+
+// lroMaxPollFailures is the number of consecutive transient failures after which LROManager stops polling an operation.
+const lroMaxPollFailures = 5
+
+// LROStore persists the resume tokens of in-flight long-running operations.
+// Implementations must be safe for concurrent use.
+type LROStore interface {
+	// Delete removes the resume token of the operation with the specified ID.
+	// Deleting an ID that isn't in the store isn't an error.
+	Delete(ctx context.Context, id string) error
+
+	// List returns the resume tokens of all stored operations, keyed by operation ID.
+	List(ctx context.Context) (map[string]string, error)
+
+	// Save stores the resume token of the operation with the specified ID, replacing any previous token.
+	Save(ctx context.Context, id, token string) error
+}
+
+// LROManagerOptions contains the optional values for NewLROManager.
+type LROManagerOptions[T any] struct {
+	// Frequency is the time to wait between polling intervals in absence of a Retry-After header.
+	// Pass zero to accept the default value (30s).
+	Frequency time.Duration
+
+	// OnComplete is called when an operation reaches a terminal state, or polling it returns an
+	// *azcore.ResponseError indicating the operation failed, e.g. because it no longer exists.
+	// err is nil when the operation succeeded. It's called from the goroutine polling the
+	// operation, which is removed from the store after OnComplete returns. Therefore, OnComplete
+	// can be called more than once for an operation when the process exits before the removal.
+	OnComplete func(id string, result T, err error)
+}
+
+// LROManager tracks long-running operations across process restarts. It persists the resume tokens
+// of the operations it tracks to an LROStore and polls them in the background until they complete.
+// After a restart, call ResumeAll to continue tracking the operations in the store.
+// All operations in the store must have result type T, so use a separate store per result type.
+type LROManager[T any] struct {
+	pl      exported.Pipeline
+	store   LROStore
+	options LROManagerOptions[T]
+
+	mu     sync.Mutex
+	active map[string]struct{}
+	wg     sync.WaitGroup
+}
+
+// NewLROManager creates an LROManager that persists resume tokens to store.
+// pl is used for polling resumed operations.
+// options: pass nil to accept the default values.
+func NewLROManager[T any](pl exported.Pipeline, store LROStore, options *LROManagerOptions[T]) *LROManager[T] {
+	m := &LROManager[T]{
+		pl:     pl,
+		store:  store,
+		active: map[string]struct{}{},
+	}
+	if options != nil {
+		m.options = *options
+	}
+	if m.options.Frequency <= 0 {
+		m.options.Frequency = 30 * time.Second
+	}
+	return m
+}
+
+// Track saves the resume token of poller to the store with the specified ID, then polls the operation in the
+// background until it completes or ctx is cancelled. Transient polling failures, such as network errors, are
+// retried with exponential backoff. When ctx is cancelled or polling fails repeatedly, the operation remains in
+// the store so it can be resumed later. Track returns an error when the token can't be saved or the manager is
+// already tracking an operation with the specified ID.
+func (m *LROManager[T]) Track(ctx context.Context, id string, poller *Poller[T]) error {
+	if !m.start(id) {
+		return fmt.Errorf("operation %s is already being tracked", id)
+	}
+	if !poller.Done() {
+		tk, err := poller.ResumeToken()
+		if err == nil {
+			err = m.store.Save(ctx, id, tk)
+		}
+		if err != nil {
+			m.stop(id)
+			return err
+		}
+	}
+	go m.poll(ctx, id, poller)
+	return nil
+}
+
+// Pending returns the IDs of the operations in the store, sorted in ascending order. This includes
+// operations that aren't being tracked, e.g. because they were started by a process that exited.
+func (m *LROManager[T]) Pending(ctx context.Context) ([]string, error) {
+	tokens, err := m.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(tokens))
+	for id := range tokens {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// ResumeAll polls in the background all operations in the store that aren't already being tracked.
+// Operations that can't be resumed remain in the store, and the error for the first of them is returned.
+// Polling stops when ctx is cancelled.
+func (m *LROManager[T]) ResumeAll(ctx context.Context) error {
+	tokens, err := m.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	var resumeErr error
+	for id, tk := range tokens {
+		poller, err := NewPollerFromResumeToken[T](tk, m.pl, nil)
+		if err != nil {
+			if resumeErr == nil {
+				resumeErr = fmt.Errorf("failed to resume operation %s: %w", id, err)
+			}
+			continue
+		}
+		if m.start(id) {
+			log.Writef(log.EventLRO, "Resuming operation %s.", id)
+			go m.poll(ctx, id, poller)
+		}
+	}
+	return resumeErr
+}
+
+// Wait blocks until polling stops for all tracked operations.
+func (m *LROManager[T]) Wait() {
+	m.wg.Wait()
+}
+
+// start marks the operation as tracked. It returns false if the operation is already tracked.
+func (m *LROManager[T]) start(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.active[id]; ok {
+		return false
+	}
+	m.active[id] = struct{}{}
+	m.wg.Add(1)
+	return true
+}
+
+// stop marks the operation as no longer tracked.
+func (m *LROManager[T]) stop(id string) {
+	m.mu.Lock()
+	delete(m.active, id)
+	m.mu.Unlock()
+	m.wg.Done()
+}
+
+// poll polls the operation until it completes, saving its latest resume token after each poll.
+func (m *LROManager[T]) poll(ctx context.Context, id string, poller *Poller[T]) {
+	defer m.stop(id)
+	failures := 0
+	for !poller.Done() {
+		resp, err := poller.Poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if operationFailed(err) {
+				m.complete(ctx, id, *new(T), err)
+				return
+			}
+			failures++
+			if !m.backoff(ctx, id, failures, err) {
+				return
+			}
+			continue
+		}
+		failures = 0
+		if poller.Done() {
+			break
+		}
+		// the token can change as the operation progresses, e.g. when the polling URL changes
+		if tk, err := poller.ResumeToken(); err == nil {
+			if err = m.store.Save(ctx, id, tk); err != nil {
+				log.Writef(log.EventLRO, "failed to save resume token for operation %s: %v", id, err)
+			}
+		}
+		d := m.options.Frequency
+		if retryAfter := shared.RetryAfter(resp); retryAfter > 0 {
+			d = retryAfter
+		}
+		if err := shared.Delay(ctx, d); err != nil {
+			return
+		}
+	}
+	for {
+		result, err := poller.Result(ctx)
+		var respErr *exported.ResponseError
+		if err == nil || errors.As(err, &respErr) {
+			m.complete(ctx, id, result, err)
+			return
+		}
+		// retrieving the result failed or was cancelled, retry or leave the operation in the store
+		failures++
+		if ctx.Err() != nil || !m.backoff(ctx, id, failures, err) {
+			return
+		}
+	}
+}
+
+// backoff waits before polling the operation again after it failed the specified number of consecutive times.
+// It returns false when the manager should stop polling the operation, leaving it in the store.
+func (m *LROManager[T]) backoff(ctx context.Context, id string, failures int, err error) bool {
+	if failures >= lroMaxPollFailures {
+		log.Writef(log.EventLRO, "Stopped polling operation %s after %d consecutive failures: %v", id, failures, err)
+		return false
+	}
+	log.Writef(log.EventLRO, "Polling operation %s failed, will retry: %v", id, err)
+	b := ExponentialPollingBackoff{InitialDelay: m.options.Frequency, MaxDelay: 8 * m.options.Frequency}
+	return shared.Delay(ctx, b.Delay(failures)) == nil
+}
+
+// operationFailed returns true when err, returned by polling an operation, indicates the operation failed
+// rather than the attempt to poll it, i.e. err is an *azcore.ResponseError with a non-transient status code.
+func operationFailed(err error) bool {
+	var respErr *exported.ResponseError
+	if !errors.As(err, &respErr) {
+		return false
+	}
+	switch respErr.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return false
+	}
+	return true
+}
+
+// complete reports the outcome of the operation and removes it from the store.
+func (m *LROManager[T]) complete(ctx context.Context, id string, result T, err error) {
+	log.Writef(log.EventLRO, "Operation %s completed: %v", id, err)
+	if m.options.OnComplete != nil {
+		m.options.OnComplete(id, result, err)
+	}
+	if err := m.store.Delete(ctx, id); err != nil {
+		log.Writef(log.EventLRO, "failed to delete resume token for operation %s: %v", id, err)
+	}
+}
@@ -0,0 +1,181 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type lroCompletion struct {
+	result mockType
+	err    error
+}
+
+func newTestLROManager(t *testing.T, srv *mock.Server, store *FileLROStore) (*LROManager[mockType], *FileLROStore, map[string]lroCompletion) {
+	if store == nil {
+		var err error
+		store, err = NewFileLROStore(t.TempDir())
+		require.NoError(t, err)
+	}
+	var mu sync.Mutex
+	completed := map[string]lroCompletion{}
+	m := NewLROManager(getPipeline(srv), store, &LROManagerOptions[mockType]{
+		Frequency: time.Millisecond,
+		OnComplete: func(id string, result mockType, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			completed[id] = lroCompletion{result: result, err: err}
+		},
+	})
+	return m, store, completed
+}
+
+func newTestAsyncPoller(t *testing.T, srv *mock.Server) *Poller[mockType] {
+	resp, _ := initialResponse(http.MethodPut, srv.URL(), strings.NewReader(provStateStarted))
+	resp.Header.Set(shared.HeaderAzureAsync, srv.URL())
+	resp.StatusCode = http.StatusCreated
+	poller, err := NewPoller[mockType](resp, getPipeline(srv), nil)
+	require.NoError(t, err)
+	return poller
+}
+
+func TestLROManagerTrack(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithBody([]byte(statusInProgress)))
+	srv.AppendResponse(mock.WithBody([]byte(statusSucceeded)))
+	srv.AppendResponse(mock.WithBody([]byte(successResp)))
+	m, _, completed := newTestLROManager(t, srv, nil)
+
+	require.NoError(t, m.Track(context.Background(), "op", newTestAsyncPoller(t, srv)))
+	m.Wait()
+	require.Len(t, completed, 1)
+	require.NoError(t, completed["op"].err)
+	require.Equal(t, "value", *completed["op"].result.Field)
+	pending, err := m.Pending(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
+
+func TestLROManagerTrackFailed(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithBody([]byte(statusCanceled)))
+	m, _, completed := newTestLROManager(t, srv, nil)
+
+	require.NoError(t, m.Track(context.Background(), "op", newTestAsyncPoller(t, srv)))
+	m.Wait()
+	require.Error(t, completed["op"].err)
+	pending, err := m.Pending(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
+
+func TestLROManagerTrackNotFound(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithStatusCode(http.StatusNotFound))
+	m, _, completed := newTestLROManager(t, srv, nil)
+
+	require.NoError(t, m.Track(context.Background(), "op", newTestAsyncPoller(t, srv)))
+	m.Wait()
+	var respErr *exported.ResponseError
+	require.ErrorAs(t, completed["op"].err, &respErr)
+	require.Equal(t, http.StatusNotFound, respErr.StatusCode)
+	pending, err := m.Pending(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
+
+func TestLROManagerTrackTransientError(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendError(&nonRetriableError{"connection reset"})
+	srv.AppendResponse(mock.WithBody([]byte(statusSucceeded)))
+	srv.AppendError(&nonRetriableError{"connection reset"})
+	srv.AppendResponse(mock.WithBody([]byte(successResp)))
+	m, _, completed := newTestLROManager(t, srv, nil)
+
+	require.NoError(t, m.Track(context.Background(), "op", newTestAsyncPoller(t, srv)))
+	m.Wait()
+	require.Len(t, completed, 1)
+	require.NoError(t, completed["op"].err)
+	require.Equal(t, "value", *completed["op"].result.Field)
+}
+
+func TestLROManagerTrackRepeatedErrors(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.SetError(&nonRetriableError{"connection reset"})
+	m, _, completed := newTestLROManager(t, srv, nil)
+
+	require.NoError(t, m.Track(context.Background(), "op", newTestAsyncPoller(t, srv)))
+	m.Wait()
+	require.Empty(t, completed)
+	require.Equal(t, lroMaxPollFailures, srv.Requests())
+	// the operation remains in the store so it can be resumed later
+	pending, err := m.Pending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"op"}, pending)
+}
+
+func TestLROManagerTrackDuplicate(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.SetResponse(mock.WithBody([]byte(statusInProgress)))
+	m, _, _ := newTestLROManager(t, srv, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, m.Track(ctx, "op", newTestAsyncPoller(t, srv)))
+	err := m.Track(ctx, "op", newTestAsyncPoller(t, srv))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already being tracked")
+	cancel()
+	m.Wait()
+}
+
+func TestLROManagerResumeAll(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	m, store, _ := newTestLROManager(t, srv, nil)
+
+	// simulate a process exiting after it started tracking the operation
+	tk, err := newTestAsyncPoller(t, srv).ResumeToken()
+	require.NoError(t, err)
+	require.NoError(t, store.Save(context.Background(), "op", tk))
+	require.NoError(t, store.Save(context.Background(), "invalid", "not a resume token"))
+	pending, err := m.Pending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"invalid", "op"}, pending)
+
+	// after the restart, a new manager resumes the operation
+	srv.AppendResponse(mock.WithBody([]byte(statusSucceeded)))
+	srv.AppendResponse(mock.WithBody([]byte(successResp)))
+	m, _, completed := newTestLROManager(t, srv, store)
+	err = m.ResumeAll(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid")
+	m.Wait()
+	require.Len(t, completed, 1)
+	require.NoError(t, completed["op"].err)
+	require.Equal(t, "value", *completed["op"].result.Field)
+
+	// the operation that couldn't be resumed remains in the store
+	pending, err = m.Pending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"invalid"}, pending)
+}
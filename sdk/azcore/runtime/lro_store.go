@@ -0,0 +1,114 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileLROStoreExt is the extension of the files containing resume tokens
+const fileLROStoreExt = ".token"
+
+// fileLROStoreEntry is the content of a FileLROStore file. It includes the operation
+// ID because the file name is a hash of the ID, from which the ID can't be recovered.
+type fileLROStoreEntry struct {
+	ID    string `json:"id"`
+	Token string `json:"token"`
+}
+
+// FileLROStore is an LROStore that keeps each resume token in a file.
+// Don't create this type directly, use NewFileLROStore instead.
+type FileLROStore struct {
+	dir string
+}
+
+// NewFileLROStore creates a FileLROStore that keeps resume tokens in dir, creating dir as required.
+// Resume tokens contain the URLs of operations, so dir should be accessible only to the current user.
+func NewFileLROStore(dir string) (*FileLROStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileLROStore{dir: dir}, nil
+}
+
+// Delete implements the LROStore interface for FileLROStore.
+func (s *FileLROStore) Delete(ctx context.Context, id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List implements the LROStore interface for FileLROStore.
+func (s *FileLROStore) List(ctx context.Context) (map[string]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	tokens := map[string]string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, fileLROStoreExt) {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(s.dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			// deleted after ReadDir
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		var entry fileLROStoreEntry
+		if err := json.Unmarshal(b, &entry); err != nil || s.path(entry.ID) != filepath.Join(s.dir, name) {
+			// not one of ours
+			continue
+		}
+		tokens[entry.ID] = entry.Token
+	}
+	return tokens, nil
+}
+
+// Save implements the LROStore interface for FileLROStore.
+// It writes the token to a temporary file then renames it, so a crash can't leave a partial token.
+func (s *FileLROStore) Save(ctx context.Context, id, token string) error {
+	b, err := json.Marshal(fileLROStoreEntry{ID: id, Token: token})
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(s.dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.path(id))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+// path returns the path of the file containing the token for the operation with the specified ID.
+// The file name is a hash of the ID, so it's valid and of fixed length regardless of the ID's characters and length.
+func (s *FileLROStore) path(id string) string {
+	h := sha256.Sum256([]byte(id))
+	return filepath.Join(s.dir, hex.EncodeToString(h[:])+fileLROStoreExt)
+}
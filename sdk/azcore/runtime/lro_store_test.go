@@ -0,0 +1,58 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileLROStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "lros")
+	s, err := NewFileLROStore(dir)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	tokens, err := s.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, tokens)
+
+	// IDs aren't necessarily valid file names, and may be longer than file names can be
+	const id1, id2 = "/subscriptions/sub/resourceGroups/rg", "vm:create"
+	id3 := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/" + strings.Repeat("vm", 200)
+	require.NoError(t, s.Save(ctx, id1, "token1"))
+	require.NoError(t, s.Save(ctx, id2, "token2"))
+	require.NoError(t, s.Save(ctx, id2, "token2 updated"))
+	require.NoError(t, s.Save(ctx, id3, "token3"))
+	// files the store didn't create are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "readme.txt"), []byte("hello"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other"+fileLROStoreExt), []byte(`{"id":"other","token":"token"}`), 0600))
+
+	tokens, err = s.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{id1: "token1", id2: "token2 updated", id3: "token3"}, tokens)
+
+	require.NoError(t, s.Delete(ctx, id3))
+
+	require.NoError(t, s.Delete(ctx, id1))
+	require.NoError(t, s.Delete(ctx, id1))
+	tokens, err = s.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{id2: "token2 updated"}, tokens)
+
+	// another store for the same directory sees the same tokens, as after a restart
+	s, err = NewFileLROStore(dir)
+	require.NoError(t, err)
+	tokens, err = s.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{id2: "token2 updated"}, tokens)
+}
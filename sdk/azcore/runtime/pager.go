@@ -20,6 +20,12 @@ type PagingHandler[T any] struct {
 
 	// Fetcher fetches the first and subsequent pages.
 	Fetcher func(context.Context, *T) (T, error)
+
+	// PrefetchDepth is the maximum number of pages to fetch in the background while the
+	// caller processes the current page. Background fetches use the context passed to the
+	// NextPage call that started them.
+	// The default value of zero disables prefetching.
+	PrefetchDepth int
 }
 
 // Pager provides operations for iterating over paged responses.
@@ -27,6 +33,7 @@ type Pager[T any] struct {
 	current   *T
 	handler   PagingHandler[T]
 	firstPage bool
+	prefetch  *prefetcher[T]
 }
 
 // NewPager creates an instance of Pager using the specified PagingHandler.
@@ -54,11 +61,12 @@ func (p *Pager[T]) NextPage(ctx context.Context) (T, error) {
 		if p.firstPage {
 			// we get here if it's an LRO-pager, we already have the first page
 			p.firstPage = false
+			p.startPrefetch(ctx)
 			return *p.current, nil
 		} else if !p.handler.More(*p.current) {
 			return *new(T), errors.New("no more pages")
 		}
-		resp, err = p.handler.Fetcher(ctx, p.current)
+		resp, err = p.fetchNext(ctx)
 	} else {
 		// non-LRO case, first page
 		p.firstPage = false
@@ -68,10 +76,108 @@ func (p *Pager[T]) NextPage(ctx context.Context) (T, error) {
 		return *new(T), err
 	}
 	p.current = &resp
+	p.startPrefetch(ctx)
 	return *p.current, nil
 }
 
+// fetchNext fetches the page following the current page.
+// If prefetching is enabled, the page is taken from the prefetched pages.
+func (p *Pager[T]) fetchNext(ctx context.Context) (T, error) {
+	if p.prefetch == nil {
+		return p.handler.Fetcher(ctx, p.current)
+	}
+	var result prefetchResult[T]
+	select {
+	case result = <-p.prefetch.results:
+	case <-p.prefetch.done:
+		// the fetch may have ended after startPrefetch last checked, in which case
+		// no fetch is in progress and the next page may not have been prefetched
+		select {
+		case result = <-p.prefetch.results:
+		default:
+			// all prefetched pages were taken, so the tail is the current page
+			page, err := p.handler.Fetcher(ctx, p.current)
+			if err == nil {
+				p.prefetch.tail = page
+			}
+			return page, err
+		}
+	case <-ctx.Done():
+		// any page being prefetched will be returned by a subsequent call
+		return *new(T), ctx.Err()
+	}
+	if result.err != nil {
+		// prefetching stops after an error, so start over from the current page
+		p.prefetch = nil
+		if ctxErr := ctx.Err(); ctxErr == nil && (errors.Is(result.err, context.Canceled) || errors.Is(result.err, context.DeadlineExceeded)) {
+			// the context used for prefetching is no longer valid but this one is
+			return p.handler.Fetcher(ctx, p.current)
+		}
+	}
+	return result.page, result.err
+}
+
+// startPrefetch starts fetching the pages following the current page in the background,
+// up to the prefetch depth. It's a no-op if prefetching is disabled or a fetch is in progress.
+func (p *Pager[T]) startPrefetch(ctx context.Context) {
+	if p.handler.PrefetchDepth <= 0 {
+		return
+	}
+	if p.prefetch == nil {
+		p.prefetch = &prefetcher[T]{
+			results: make(chan prefetchResult[T], p.handler.PrefetchDepth),
+			tail:    *p.current,
+		}
+	} else if p.prefetch.done != nil {
+		select {
+		case <-p.prefetch.done:
+		default:
+			// still fetching
+			return
+		}
+	}
+	if count := p.handler.PrefetchDepth - len(p.prefetch.results); count > 0 && p.handler.More(p.prefetch.tail) {
+		p.prefetch.done = make(chan struct{})
+		go p.prefetch.fetch(ctx, p.handler, count)
+	}
+}
+
 // UnmarshalJSON implements the json.Unmarshaler interface for Pager[T].
 func (p *Pager[T]) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, &p.current)
 }
+
+// prefetchResult is the outcome of fetching a page in the background.
+type prefetchResult[T any] struct {
+	page T
+	err  error
+}
+
+// prefetcher fetches pages in the background.
+type prefetcher[T any] struct {
+	// results contains the prefetched pages, in order.
+	// its capacity is the prefetch depth, so sending never blocks.
+	results chan prefetchResult[T]
+
+	// done is closed when the goroutine fetching pages exits
+	done chan struct{}
+
+	// tail is the last page fetched. it's owned by the
+	// goroutine fetching pages until done is closed.
+	tail T
+}
+
+// fetch fetches up to count pages following the tail.
+// it stops after an error or when there are no more pages.
+func (pf *prefetcher[T]) fetch(ctx context.Context, handler PagingHandler[T], count int) {
+	defer close(pf.done)
+	for i := 0; i < count && handler.More(pf.tail); i++ {
+		page, err := handler.Fetcher(ctx, &pf.tail)
+		if err != nil {
+			pf.results <- prefetchResult[T]{err: err}
+			return
+		}
+		pf.tail = page
+		pf.results <- prefetchResult[T]{page: page}
+	}
+}
@@ -0,0 +1,70 @@
+//go:build go1.23
+// +build go1.23
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"iter"
+)
+
+// Pages returns an iterator over the remaining pages of the Pager.
+// Each iteration fetches the next page. If fetching a page fails, the error is yielded
+// with the zero-value of T and the iteration stops.
+// Breaking out of the loop stops fetching pages. Iterating again resumes with the next page.
+// ctx is used for fetching the pages. If it's cancelled, its error is yielded before the next fetch.
+//
+//	for page, err := range pager.Pages(ctx) {
+//		if err != nil {
+//			// handle error...
+//		}
+//		// use page...
+//	}
+func (p *Pager[T]) Pages(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for p.More() {
+			if err := ctx.Err(); err != nil {
+				yield(*new(T), err)
+				return
+			}
+			page, err := p.NextPage(ctx)
+			if err != nil {
+				yield(*new(T), err)
+				return
+			}
+			if !yield(page, nil) {
+				return
+			}
+		}
+	}
+}
+
+// PagerItems returns an iterator over the items in the remaining pages of the Pager.
+// It has the same semantics as Pager[T].Pages, except that the items of each page are yielded individually.
+// Pages are only fetched once all the items of the previous page have been yielded.
+// items returns the items in a page, typically the page's Value field.
+// ctx is used for fetching the pages. If it's cancelled, its error is yielded before the next fetch.
+//
+//	for vm, err := range runtime.PagerItems(ctx, pager, func(page armcompute.VirtualMachinesClientListResponse) []*armcompute.VirtualMachine {
+//		return page.Value
+//	}) {
+//		// ...
+//	}
+func PagerItems[T, I any](ctx context.Context, pager *Pager[T], items func(T) []I) iter.Seq2[I, error] {
+	return func(yield func(I, error) bool) {
+		for page, err := range pager.Pages(ctx) {
+			if err != nil {
+				yield(*new(I), err)
+				return
+			}
+			for _, item := range items(page) {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
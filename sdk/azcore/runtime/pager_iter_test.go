@@ -0,0 +1,138 @@
+//go:build go1.23
+// +build go1.23
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestIterPager returns a Pager over the specified pages.
+// The error is returned when fetching the page at index errAt.
+func newTestIterPager(pages [][]int, errAt int, err error) (*Pager[PageResponse], *int) {
+	fetched := 0
+	return NewPager(PagingHandler[PageResponse]{
+		More: func(current PageResponse) bool {
+			return current.NextPage
+		},
+		Fetcher: func(ctx context.Context, current *PageResponse) (PageResponse, error) {
+			if fetched == errAt {
+				return PageResponse{}, err
+			}
+			page := PageResponse{Values: pages[fetched], NextPage: fetched < len(pages)-1}
+			fetched++
+			return page, nil
+		},
+	}), &fetched
+}
+
+func TestPagerPages(t *testing.T) {
+	pager, fetched := newTestIterPager([][]int{{1, 2}, {3}, {4, 5}}, -1, nil)
+	var values []int
+	for page, err := range pager.Pages(context.Background()) {
+		require.NoError(t, err)
+		values = append(values, page.Values...)
+	}
+	require.Equal(t, []int{1, 2, 3, 4, 5}, values)
+	require.Equal(t, 3, *fetched)
+	require.False(t, pager.More())
+}
+
+func TestPagerPagesEarlyTermination(t *testing.T) {
+	pager, fetched := newTestIterPager([][]int{{1, 2}, {3}, {4, 5}}, -1, nil)
+	for page, err := range pager.Pages(context.Background()) {
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2}, page.Values)
+		break
+	}
+	require.Equal(t, 1, *fetched)
+
+	// iterating again resumes with the next page
+	var values []int
+	for page, err := range pager.Pages(context.Background()) {
+		require.NoError(t, err)
+		values = append(values, page.Values...)
+	}
+	require.Equal(t, []int{3, 4, 5}, values)
+}
+
+func TestPagerPagesError(t *testing.T) {
+	fetchErr := errors.New("failed")
+	pager, _ := newTestIterPager([][]int{{1, 2}, {3}}, 1, fetchErr)
+	var errs []error
+	pages := 0
+	for page, err := range pager.Pages(context.Background()) {
+		if err != nil {
+			require.Zero(t, page)
+			errs = append(errs, err)
+			continue
+		}
+		pages++
+	}
+	require.Equal(t, 1, pages)
+	require.Equal(t, []error{fetchErr}, errs)
+}
+
+func TestPagerPagesCancelled(t *testing.T) {
+	pager, fetched := newTestIterPager([][]int{{1, 2}, {3}}, -1, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var errs []error
+	for _, err := range pager.Pages(ctx) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		cancel()
+	}
+	require.Equal(t, []error{context.Canceled}, errs)
+	require.Equal(t, 1, *fetched)
+}
+
+func TestPagerItems(t *testing.T) {
+	getItems := func(page PageResponse) []int {
+		return page.Values
+	}
+
+	pager, _ := newTestIterPager([][]int{{1, 2}, {}, {3, 4, 5}}, -1, nil)
+	var values []int
+	for v, err := range PagerItems(context.Background(), pager, getItems) {
+		require.NoError(t, err)
+		values = append(values, v)
+	}
+	require.Equal(t, []int{1, 2, 3, 4, 5}, values)
+
+	// stop after N items, the remaining pages aren't fetched
+	pager, fetched := newTestIterPager([][]int{{1, 2}, {3, 4}, {5}}, -1, nil)
+	values = nil
+	for v, err := range PagerItems(context.Background(), pager, getItems) {
+		require.NoError(t, err)
+		values = append(values, v)
+		if len(values) == 3 {
+			break
+		}
+	}
+	require.Equal(t, []int{1, 2, 3}, values)
+	require.Equal(t, 2, *fetched)
+
+	fetchErr := errors.New("failed")
+	pager, _ = newTestIterPager([][]int{{1, 2}, {3}}, 1, fetchErr)
+	values = nil
+	var errs []error
+	for v, err := range PagerItems(context.Background(), pager, getItems) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		values = append(values, v)
+	}
+	require.Equal(t, []int{1, 2}, values)
+	require.Equal(t, []error{fetchErr}, errs)
+}
@@ -11,7 +11,11 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/mock"
@@ -256,3 +260,187 @@ func TestPagerResponderError(t *testing.T) {
 	require.Error(t, err)
 	require.Empty(t, page)
 }
+
+func TestPagerPrefetch(t *testing.T) {
+	const pages = 10
+	const depth = 3
+	var fetched int32
+	pager := NewPager(PagingHandler[PageResponse]{
+		More: func(current PageResponse) bool {
+			return current.NextPage
+		},
+		Fetcher: func(ctx context.Context, current *PageResponse) (PageResponse, error) {
+			i := 0
+			if current != nil {
+				i = current.Values[0] + 1
+			}
+			atomic.AddInt32(&fetched, 1)
+			return PageResponse{Values: []int{i}, NextPage: i < pages-1}, nil
+		},
+		PrefetchDepth: depth,
+	})
+
+	pageCount := 0
+	for pager.More() {
+		page, err := pager.NextPage(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []int{pageCount}, page.Values)
+		pageCount++
+		// the pager shouldn't fetch more than depth pages ahead of the caller
+		require.LessOrEqual(t, int(atomic.LoadInt32(&fetched)), pageCount+depth)
+	}
+	require.Equal(t, pages, pageCount)
+	require.EqualValues(t, pages, atomic.LoadInt32(&fetched))
+	_, err := pager.NextPage(context.Background())
+	require.Error(t, err)
+}
+
+func TestPagerPrefetchInBackground(t *testing.T) {
+	const depth = 2
+	var mu sync.Mutex
+	fetched := 0
+	pager := NewPager(PagingHandler[PageResponse]{
+		More: func(current PageResponse) bool {
+			return current.NextPage
+		},
+		Fetcher: func(ctx context.Context, current *PageResponse) (PageResponse, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			fetched++
+			return PageResponse{Values: []int{fetched}, NextPage: true}, nil
+		},
+		PrefetchDepth: depth,
+	})
+
+	page, err := pager.NextPage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, page.Values)
+	// the next depth pages should be fetched while the caller processes the first one
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return fetched == depth+1
+	}, 5*time.Second, time.Millisecond)
+	// and no more than that
+	time.Sleep(10 * time.Millisecond)
+	mu.Lock()
+	require.Equal(t, depth+1, fetched)
+	mu.Unlock()
+	page, err = pager.NextPage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{2}, page.Values)
+}
+
+func TestPagerPrefetchError(t *testing.T) {
+	fetchErr := errors.New("fetch failed")
+	fail := true
+	pager := NewPager(PagingHandler[PageResponse]{
+		More: func(current PageResponse) bool {
+			return current.NextPage
+		},
+		Fetcher: func(ctx context.Context, current *PageResponse) (PageResponse, error) {
+			if current == nil {
+				return PageResponse{Values: []int{0}, NextPage: true}, nil
+			}
+			if current.Values[0] == 1 && fail {
+				fail = false
+				return PageResponse{}, fetchErr
+			}
+			return PageResponse{Values: []int{current.Values[0] + 1}, NextPage: current.Values[0] < 2}, nil
+		},
+		PrefetchDepth: 2,
+	})
+
+	page, err := pager.NextPage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{0}, page.Values)
+	page, err = pager.NextPage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, page.Values)
+	// the error is returned in place of the page that failed to fetch
+	_, err = pager.NextPage(context.Background())
+	require.ErrorIs(t, err, fetchErr)
+	// the pager can continue after an error
+	require.True(t, pager.More())
+	page, err = pager.NextPage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{2}, page.Values)
+	page, err = pager.NextPage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{3}, page.Values)
+	require.False(t, pager.More())
+}
+
+func TestPagerPrefetchContextCanceled(t *testing.T) {
+	pager := NewPager(PagingHandler[PageResponse]{
+		More: func(current PageResponse) bool {
+			return current.NextPage
+		},
+		Fetcher: func(ctx context.Context, current *PageResponse) (PageResponse, error) {
+			if err := ctx.Err(); err != nil {
+				return PageResponse{}, err
+			}
+			i := 0
+			if current != nil {
+				i = current.Values[0] + 1
+			}
+			return PageResponse{Values: []int{i}, NextPage: i < 1}, nil
+		},
+		PrefetchDepth: 1,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	page, err := pager.NextPage(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int{0}, page.Values)
+	cancel()
+
+	// the prefetch may have failed due to the canceled context, in which
+	// case the pager should fetch the page again with the new context
+	page, err = pager.NextPage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, page.Values)
+	require.False(t, pager.More())
+}
+
+func TestPagerPrefetchConcurrent(t *testing.T) {
+	// the background fetch can send its last page before startPrefetch observes that it
+	// ended. Many concurrent pagers make it likely a caller takes that page in the gap.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+	const pagers = 20000
+	const pages = 3
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	wg := sync.WaitGroup{}
+	for i := 0; i < pagers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pager := NewPager(PagingHandler[PageResponse]{
+				More: func(current PageResponse) bool {
+					return current.NextPage
+				},
+				Fetcher: func(ctx context.Context, current *PageResponse) (PageResponse, error) {
+					i := 0
+					if current != nil {
+						i = current.Values[0] + 1
+					}
+					return PageResponse{Values: []int{i}, NextPage: i < pages-1}, nil
+				},
+				PrefetchDepth: 1,
+			})
+			for i := 0; pager.More(); i++ {
+				page, err := pager.NextPage(ctx)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if page.Values[0] != i {
+					t.Errorf("expected page %d, got %d", i, page.Values[0])
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -27,6 +27,8 @@ type Pipeline = exported.Pipeline
 // NewPipeline creates a pipeline from connection options, with any additional policies as specified.
 // Policies from ClientOptions are placed after policies from PipelineOptions.
 // The module and version parameters are used by the telemetry policy, when enabled.
+// When ClientOptions.TracingProvider is set, a span is created for each HTTP request
+// sent through the pipeline; see StartSpan for nesting these spans under a client operation.
 func NewPipeline(module, version string, plOpts PipelineOptions, options *policy.ClientOptions) Pipeline {
 	cp := policy.ClientOptions{}
 	if options != nil {
@@ -47,6 +49,9 @@ func NewPipeline(module, version string, plOpts PipelineOptions, options *policy
 	// we put the includeResponsePolicy at the very beginning so that the raw response
 	// is populated with the final response (some policies might mutate the response)
 	policies := []policy.Policy{policyFunc(includeResponsePolicy)}
+	if meter := cp.MetricsProvider.NewMeter(module, version); meter.Enabled() {
+		policies = append(policies, newMetricsPolicy(meter))
+	}
 	if cp.APIVersion != "" {
 		policies = append(policies, newAPIVersionPolicy(cp.APIVersion, &plOpts.APIVersion))
 	}
@@ -58,12 +63,22 @@ func NewPipeline(module, version string, plOpts PipelineOptions, options *policy
 	policies = append(policies, NewRetryPolicy(&cp.Retry))
 	policies = append(policies, plOpts.PerRetry...)
 	policies = append(policies, cp.PerRetryPolicies...)
-	policies = append(policies, NewLogPolicy(&cp.Logging))
-	policies = append(policies, policyFunc(httpHeaderPolicy), policyFunc(bodyDownloadPolicy))
 	transport := cp.Transport
 	if transport == nil {
 		transport = defaultHTTPClient
 	}
+	if cp.CircuitBreaker.FailureThreshold > 0 {
+		policies = append(policies, newCircuitBreakerPolicy(cp.CircuitBreaker))
+	}
+	if cp.Hedging.Delay > 0 || cp.Hedging.Percentile > 0 {
+		policies = append(policies, newHedgingPolicy(cp.Hedging))
+	}
+	if cp.RateLimit.RequestsPerSecond > 0 {
+		policies = append(policies, newRateLimitPolicy(cp.RateLimit))
+	}
+	policies = append(policies, newHTTPTracePolicy(cp.TracingProvider.NewTracer(module, version), cp.Logging.AllowedQueryParams))
+	policies = append(policies, NewLogPolicy(&cp.Logging))
+	policies = append(policies, policyFunc(httpHeaderPolicy), policyFunc(bodyDownloadPolicy))
 	return exported.NewPipeline(transport, policies...)
 }
 
@@ -6,39 +6,26 @@ package runtime
 import (
 	"errors"
 	"net/http"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/errorinfo"
-	"github.com/Azure/azure-sdk-for-go/sdk/internal/temporal"
 )
 
 // BearerTokenPolicy authorizes requests with bearer tokens acquired from a TokenCredential.
 type BearerTokenPolicy struct {
-	// mainResource is the resource to be retreived using the tenant specified in the credential
-	mainResource *temporal.Resource[exported.AccessToken, acquiringResourceState]
+	// mainResource is the token cache of the policy's requests when tokens aren't shared
+	mainResource *tokenCache
 	// the following fields are read-only
-	authzHandler policy.AuthorizationHandler
-	cred         exported.TokenCredential
-	scopes       []string
-}
-
-type acquiringResourceState struct {
-	req *policy.Request
-	p   *BearerTokenPolicy
-	tro policy.TokenRequestOptions
-}
-
-// acquire acquires or updates the resource; only one
-// thread/goroutine at a time ever calls this function
-func acquire(state acquiringResourceState) (newResource exported.AccessToken, newExpiration time.Time, err error) {
-	tk, err := state.p.cred.GetToken(state.req.Raw().Context(), state.tro)
-	if err != nil {
-		return exported.AccessToken{}, time.Time{}, err
-	}
-	return tk, tk.ExpiresOn, nil
+	authzHandler     policy.AuthorizationHandler
+	cred             exported.TokenCredential
+	scopes           []string
+	proactiveRefresh bool
+	sharedTokens     bool
 }
 
 // NewBearerTokenPolicy creates a policy object that authorizes requests with bearer tokens.
@@ -49,19 +36,33 @@ func NewBearerTokenPolicy(cred exported.TokenCredential, scopes []string, opts *
 	if opts == nil {
 		opts = &policy.BearerTokenOptions{}
 	}
-	return &BearerTokenPolicy{
-		authzHandler: opts.AuthorizationHandler,
-		cred:         cred,
-		scopes:       scopes,
-		mainResource: temporal.NewResource(acquire),
+	b := &BearerTokenPolicy{
+		authzHandler:     opts.AuthorizationHandler,
+		cred:             cred,
+		scopes:           scopes,
+		proactiveRefresh: opts.ProactiveRefresh,
+		// tokens are shared only by pointer credentials because a value credential may not be a valid map key
+		// even when its type is comparable, e.g. a struct having an interface field holding a func
+		sharedTokens: opts.SharedTokenCache && cred != nil && reflect.TypeOf(cred).Kind() == reflect.Ptr,
+		mainResource: newTokenCache(cred, opts.ProactiveRefresh),
 	}
+	return b
 }
 
-// authenticateAndAuthorize returns a function which authorizes req with a token from the policy's credential
-func (b *BearerTokenPolicy) authenticateAndAuthorize(req *policy.Request) func(policy.TokenRequestOptions) error {
+// tokenCache returns the cache of tokens for tro
+func (b *BearerTokenPolicy) tokenCache(tro policy.TokenRequestOptions) *tokenCache {
+	if !b.sharedTokens {
+		return b.mainResource
+	}
+	return getSharedTokenCache(b.cred, strings.Join(tro.Scopes, " "), b.proactiveRefresh, time.Now())
+}
+
+// authenticateAndAuthorize returns a function which authorizes req with a token from the policy's credential.
+// The function sets *tc to the cache of the token it used.
+func (b *BearerTokenPolicy) authenticateAndAuthorize(req *policy.Request, tc **tokenCache) func(policy.TokenRequestOptions) error {
 	return func(tro policy.TokenRequestOptions) error {
-		as := acquiringResourceState{p: b, req: req, tro: tro}
-		tk, err := b.mainResource.Get(as)
+		*tc = b.tokenCache(tro)
+		tk, err := (*tc).get(req, tro)
 		if err != nil {
 			return err
 		}
@@ -73,10 +74,11 @@ func (b *BearerTokenPolicy) authenticateAndAuthorize(req *policy.Request) func(p
 // Do authorizes a request with a bearer token
 func (b *BearerTokenPolicy) Do(req *policy.Request) (*http.Response, error) {
 	var err error
+	var tc *tokenCache
 	if b.authzHandler.OnRequest != nil {
-		err = b.authzHandler.OnRequest(req, b.authenticateAndAuthorize(req))
+		err = b.authzHandler.OnRequest(req, b.authenticateAndAuthorize(req, &tc))
 	} else {
-		err = b.authenticateAndAuthorize(req)(policy.TokenRequestOptions{Scopes: b.scopes})
+		err = b.authenticateAndAuthorize(req, &tc)(policy.TokenRequestOptions{Scopes: b.scopes})
 	}
 	if err != nil {
 		return nil, ensureNonRetriable(err)
@@ -88,9 +90,11 @@ func (b *BearerTokenPolicy) Do(req *policy.Request) (*http.Response, error) {
 	}
 
 	if res.StatusCode == http.StatusUnauthorized {
-		b.mainResource.Expire()
+		if tc != nil {
+			tc.expire()
+		}
 		if res.Header.Get("WWW-Authenticate") != "" && b.authzHandler.OnChallenge != nil {
-			if err = b.authzHandler.OnChallenge(req, res, b.authenticateAndAuthorize(req)); err == nil {
+			if err = b.authzHandler.OnChallenge(req, res, b.authenticateAndAuthorize(req, &tc)); err == nil {
 				res, err = req.Next()
 			}
 		}
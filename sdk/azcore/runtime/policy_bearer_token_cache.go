@@ -0,0 +1,247 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/metrics"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const (
+	// tokenRefreshWindow is how long before a token expires a request refreshes it. Other requests use the
+	// token while it's refreshed. Proactive refresh happens at the beginning of this window.
+	tokenRefreshWindow = 5 * time.Minute
+
+	// tokenRefreshBackoff is the minimum time between attempts to refresh a token that hasn't expired
+	tokenRefreshBackoff = 30 * time.Second
+
+	// tokenRefreshTimeout bounds the duration of a proactive refresh
+	tokenRefreshTimeout = time.Minute
+
+	// sharedTokenCacheIdleTimeout is how long a shared token cache can go unused before it's deleted
+	sharedTokenCacheIdleTimeout = time.Hour
+)
+
+// sharedTokenCacheKey identifies the tokens shared by BearerTokenPolicy instances.
+// Credentials request tokens from their own tenant, so the credential identifies the tenant.
+type sharedTokenCacheKey struct {
+	cred   exported.TokenCredential
+	scopes string
+}
+
+// sharedTokenCacheEntry is a shared token cache and when a policy last used it.
+type sharedTokenCacheEntry struct {
+	tc       *tokenCache
+	lastUsed time.Time
+}
+
+var (
+	// sharedTokenCaches contains the tokens shared by BearerTokenPolicy instances. An entry is
+	// deleted when it has been idle for sharedTokenCacheIdleTimeout, so caches of credentials
+	// the application no longer uses don't accumulate.
+	sharedTokenCaches   = map[sharedTokenCacheKey]*sharedTokenCacheEntry{}
+	sharedTokenCachesMu sync.Mutex
+	// sharedTokenCachesSwept is when idle entries were last deleted
+	sharedTokenCachesSwept time.Time
+)
+
+// getSharedTokenCache returns the process-wide cache of cred's tokens for scopes, creating it as required.
+// cred must be a pointer, so it's a valid map key.
+func getSharedTokenCache(cred exported.TokenCredential, scopes string, proactiveRefresh bool, now time.Time) *tokenCache {
+	sharedTokenCachesMu.Lock()
+	defer sharedTokenCachesMu.Unlock()
+	if now.Sub(sharedTokenCachesSwept) > sharedTokenCacheIdleTimeout/4 {
+		deleteIdleSharedTokenCaches(now)
+	}
+	key := sharedTokenCacheKey{cred: cred, scopes: scopes}
+	e, ok := sharedTokenCaches[key]
+	if !ok {
+		e = &sharedTokenCacheEntry{tc: newTokenCache(cred, proactiveRefresh)}
+		sharedTokenCaches[key] = e
+	} else if proactiveRefresh {
+		// the token is refreshed proactively when any policy sharing it wants that
+		e.tc.cond.L.Lock()
+		e.tc.proactiveRefresh = true
+		e.tc.cond.L.Unlock()
+	}
+	e.lastUsed = now
+	return e.tc
+}
+
+// deleteIdleSharedTokenCaches deletes the shared token caches that have been idle for
+// sharedTokenCacheIdleTimeout. Must be called with sharedTokenCachesMu held.
+func deleteIdleSharedTokenCaches(now time.Time) {
+	for key, e := range sharedTokenCaches {
+		if now.Sub(e.lastUsed) > sharedTokenCacheIdleTimeout {
+			delete(sharedTokenCaches, key)
+			e.tc.stop()
+		}
+	}
+	sharedTokenCachesSwept = now
+}
+
+// tokenCache is an access token requests share. A request refreshes the token when it's expired or about to
+// expire, and with proactive refresh enabled, the token is refreshed in the background before requests need
+// to do so, provided requests used it since it was last refreshed.
+type tokenCache struct {
+	// cond is used to synchronize access to the shared token embodied by the remaining fields
+	cond *sync.Cond
+
+	// acquiring indicates that some goroutine is in the process of acquiring/updating the token
+	acquiring bool
+
+	// token is the cached token
+	token exported.AccessToken
+
+	// expiration indicates when the token expires; it is 0 if the token was never acquired or was expired by expire()
+	expiration time.Time
+
+	// lastAttempt indicates when a goroutine last attempted to acquire/update the token
+	lastAttempt time.Time
+
+	// the following fields support proactive refresh
+
+	proactiveRefresh bool
+	// timer is set when a proactive refresh is scheduled
+	timer *time.Timer
+	// tro are the options of the last acquired token
+	tro policy.TokenRequestOptions
+	// used indicates that a request used the token since it was last refreshed
+	used bool
+
+	cred exported.TokenCredential
+}
+
+func newTokenCache(cred exported.TokenCredential, proactiveRefresh bool) *tokenCache {
+	return &tokenCache{cond: sync.NewCond(&sync.Mutex{}), cred: cred, proactiveRefresh: proactiveRefresh}
+}
+
+// get returns a token for req, acquiring it when the cached token has expired or is about to.
+func (c *tokenCache) get(req *policy.Request, tro policy.TokenRequestOptions) (exported.AccessToken, error) {
+	now := time.Now()
+	c.cond.L.Lock()
+	for {
+		expired := c.expiration.IsZero() || c.expiration.Before(now)
+		if !c.acquiring && (expired || (c.expiration.Add(-tokenRefreshWindow).Before(now) && c.lastAttempt.Add(tokenRefreshBackoff).Before(now))) {
+			// this goroutine will acquire the token; other goroutines wait for it when the token has expired,
+			// or use the current token while it's refreshed
+			c.acquiring = true
+			c.cond.L.Unlock()
+			return c.acquire(req.Raw().Context(), req, tro, expired)
+		}
+		if !expired {
+			tk := c.token
+			c.used = true
+			c.schedule()
+			c.cond.L.Unlock()
+			return tk, nil
+		}
+		// wait for another goroutine to acquire the token
+		c.cond.Wait()
+	}
+}
+
+// acquire gets a token from the credential. Only the goroutine that set c.acquiring calls it.
+// req is nil when the token is refreshed proactively.
+func (c *tokenCache) acquire(ctx context.Context, req *policy.Request, tro policy.TokenRequestOptions, expired bool) (exported.AccessToken, error) {
+	start := time.Now()
+	tk, err := c.cred.GetToken(ctx, tro)
+	if req != nil {
+		var attrs []metrics.Attribute
+		if err != nil {
+			attrs = append(attrs, metrics.Attribute{Key: metricAttrErrorType, Value: metricErrorType(err)})
+		}
+		recordDuration(ctx, getPipelineMetrics(req).tokenDuration, start, attrs...)
+	}
+
+	c.cond.L.Lock()
+	defer func() {
+		c.acquiring = false
+		c.cond.L.Unlock()
+		// wake up any goroutines waiting for the token
+		c.cond.Broadcast()
+	}()
+	c.lastAttempt = start
+	if err != nil {
+		if expired {
+			return exported.AccessToken{}, err
+		}
+		// an eager refresh failed; the current token is still valid
+		if req == nil {
+			// try the proactive refresh again
+			c.used = true
+		}
+		c.schedule()
+		return c.token, nil
+	}
+	c.token, c.expiration, c.tro = tk, tk.ExpiresOn, tro
+	// a proactively refreshed token isn't used until a request gets it
+	c.used = req != nil
+	c.schedule()
+	return tk, nil
+}
+
+// schedule schedules a proactive refresh of the token, when enabled. Must be called with the lock held.
+func (c *tokenCache) schedule() {
+	if !c.proactiveRefresh || c.timer != nil || c.expiration.IsZero() {
+		return
+	}
+	now := time.Now()
+	d := c.expiration.Add(-tokenRefreshWindow).Sub(now)
+	if d <= 0 {
+		// the token is already in the refresh window; don't refresh more often than the backoff allows
+		d = c.lastAttempt.Add(tokenRefreshBackoff).Sub(now)
+	}
+	if now.Add(d).After(c.expiration) {
+		// the token will have expired by then, so the next request will refresh it
+		return
+	}
+	c.timer = time.AfterFunc(d, c.refresh)
+}
+
+// refresh proactively refreshes the token if requests used it since it was last refreshed.
+func (c *tokenCache) refresh() {
+	c.cond.L.Lock()
+	c.timer = nil
+	if !c.used || c.acquiring {
+		// the token is idle, or a request is refreshing it
+		c.cond.L.Unlock()
+		return
+	}
+	c.acquiring = true
+	expired := c.expiration.IsZero() || c.expiration.Before(time.Now())
+	tro := c.tro
+	c.cond.L.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRefreshTimeout)
+	defer cancel()
+	_, _ = c.acquire(ctx, nil, tro, expired)
+}
+
+// stop cancels any scheduled proactive refresh and prevents further ones.
+func (c *tokenCache) stop() {
+	c.cond.L.Lock()
+	defer c.cond.L.Unlock()
+	c.proactiveRefresh = false
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+}
+
+// expire marks the token as expired, ensuring it's refreshed by the next call to get().
+func (c *tokenCache) expire() {
+	c.cond.L.Lock()
+	defer c.cond.L.Unlock()
+	// reset the expiration as if we never got this token to begin with
+	c.expiration = time.Time{}
+}
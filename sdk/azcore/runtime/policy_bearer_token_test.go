@@ -9,6 +9,7 @@ import (
 
 	"errors"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -225,3 +226,152 @@ func TestBearerTokenPolicy_AuthZHandlerErrors(t *testing.T) {
 		require.Equal(t, i+1, srv.Requests())
 	}
 }
+
+// countingCredential is a comparable credential that counts its GetToken calls
+type countingCredential struct {
+	mu    sync.Mutex
+	calls int
+	// lifetime returns the lifetime of the nth token, starting at 1
+	lifetime func(n int) time.Duration
+}
+
+func (c *countingCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (exported.AccessToken, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	lifetime := time.Hour
+	if c.lifetime != nil {
+		lifetime = c.lifetime(c.calls)
+	}
+	return exported.AccessToken{Token: fmt.Sprint(c.calls), ExpiresOn: time.Now().Add(lifetime)}, nil
+}
+
+func (c *countingCredential) Calls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+func sendAuthorizedTestRequest(t *testing.T, pl Pipeline) string {
+	req, err := NewRequest(context.Background(), http.MethodGet, "https://localhost")
+	require.NoError(t, err)
+	resp, err := pl.Do(req)
+	require.NoError(t, err)
+	return resp.Request.Header.Get(shared.HeaderAuthorization)
+}
+
+func newBearerTokenTestPipeline(policies ...policy.Policy) Pipeline {
+	return newTestPipeline(&policy.ClientOptions{
+		PerRetryPolicies: policies,
+		Transport: shared.TransportFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+		}),
+	})
+}
+
+func TestBearerTokenPolicy_ProactiveRefresh(t *testing.T) {
+	cred := &countingCredential{lifetime: func(n int) time.Duration {
+		// the first token enters the refresh window soon after it's acquired
+		return tokenRefreshWindow + time.Duration(n)*50*time.Millisecond
+	}}
+	b := NewBearerTokenPolicy(cred, []string{scope}, &policy.BearerTokenOptions{ProactiveRefresh: true})
+	pl := newBearerTokenTestPipeline(b)
+	require.Equal(t, shared.BearerTokenPrefix+"1", sendAuthorizedTestRequest(t, pl))
+
+	// the used token should be refreshed in the background
+	require.Eventually(t, func() bool { return cred.Calls() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	// the refreshed token isn't used, so it shouldn't be refreshed again
+	time.Sleep(300 * time.Millisecond)
+	require.Equal(t, 2, cred.Calls())
+
+	// requests use the proactively refreshed token; they don't have to wait for a new one
+	require.Equal(t, shared.BearerTokenPrefix+"2", sendAuthorizedTestRequest(t, pl))
+}
+
+func TestBearerTokenPolicy_NoProactiveRefresh(t *testing.T) {
+	cred := &countingCredential{lifetime: func(int) time.Duration { return tokenRefreshWindow + 50*time.Millisecond }}
+	b := NewBearerTokenPolicy(cred, []string{scope}, nil)
+	pl := newBearerTokenTestPipeline(b)
+	sendAuthorizedTestRequest(t, pl)
+	time.Sleep(200 * time.Millisecond)
+	require.Equal(t, 1, cred.Calls())
+}
+
+func TestBearerTokenPolicy_SharedTokenCache(t *testing.T) {
+	cred := &countingCredential{}
+	opts := policy.BearerTokenOptions{SharedTokenCache: true}
+	a := newBearerTokenTestPipeline(NewBearerTokenPolicy(cred, []string{scope}, &opts))
+	b := newBearerTokenTestPipeline(NewBearerTokenPolicy(cred, []string{scope}, &opts))
+	require.Equal(t, sendAuthorizedTestRequest(t, a), sendAuthorizedTestRequest(t, b))
+	require.Equal(t, 1, cred.Calls())
+
+	// tokens for other scopes aren't shared
+	c := newBearerTokenTestPipeline(NewBearerTokenPolicy(cred, []string{"other"}, &opts))
+	sendAuthorizedTestRequest(t, c)
+	require.Equal(t, 2, cred.Calls())
+
+	// nor are the tokens of policies that don't opt in
+	d := newBearerTokenTestPipeline(NewBearerTokenPolicy(cred, []string{scope}, nil))
+	sendAuthorizedTestRequest(t, d)
+	require.Equal(t, 3, cred.Calls())
+
+	// tokens from a credential that isn't comparable can't be shared
+	calls := 0
+	mc := mockCredential{getTokenImpl: func(context.Context, policy.TokenRequestOptions) (exported.AccessToken, error) {
+		calls++
+		return exported.AccessToken{Token: "***", ExpiresOn: time.Now().Add(time.Hour)}, nil
+	}}
+	for i := 0; i < 2; i++ {
+		sendAuthorizedTestRequest(t, newBearerTokenTestPipeline(NewBearerTokenPolicy(mc, []string{scope}, &opts)))
+	}
+	require.Equal(t, 2, calls)
+
+	// nor can the tokens of a value credential whose type is comparable, because its value may not be
+	type wrapper struct {
+		exported.TokenCredential
+	}
+	for i := 0; i < 2; i++ {
+		sendAuthorizedTestRequest(t, newBearerTokenTestPipeline(NewBearerTokenPolicy(wrapper{mc}, []string{scope}, &opts)))
+	}
+	require.Equal(t, 4, calls)
+}
+
+func TestBearerTokenPolicy_SharedTokenCacheIdle(t *testing.T) {
+	cred := &countingCredential{}
+	key := sharedTokenCacheKey{cred: cred, scopes: scope}
+	cached := func() bool {
+		sharedTokenCachesMu.Lock()
+		defer sharedTokenCachesMu.Unlock()
+		_, ok := sharedTokenCaches[key]
+		return ok
+	}
+	opts := policy.BearerTokenOptions{ProactiveRefresh: true, SharedTokenCache: true}
+	pl := newBearerTokenTestPipeline(NewBearerTokenPolicy(cred, []string{scope}, &opts))
+	sendAuthorizedTestRequest(t, pl)
+	require.True(t, cached())
+	tc := getSharedTokenCache(cred, scope, true, time.Now())
+
+	// the cache isn't deleted while it's in use
+	now := time.Now().Add(sharedTokenCacheIdleTimeout / 2)
+	getSharedTokenCache(cred, scope, true, now)
+	sharedTokenCachesMu.Lock()
+	deleteIdleSharedTokenCaches(now.Add(sharedTokenCacheIdleTimeout / 2))
+	sharedTokenCachesMu.Unlock()
+	require.True(t, cached())
+
+	// it's deleted, and its proactive refresh stopped, after it's idle for the timeout
+	sharedTokenCachesMu.Lock()
+	deleteIdleSharedTokenCaches(now.Add(sharedTokenCacheIdleTimeout + time.Second))
+	sharedTokenCachesMu.Unlock()
+	require.False(t, cached())
+	tc.cond.L.Lock()
+	require.False(t, tc.proactiveRefresh)
+	require.Nil(t, tc.timer)
+	tc.cond.L.Unlock()
+
+	// the policy then gets a new cache
+	sendAuthorizedTestRequest(t, pl)
+	require.True(t, cached())
+	require.Equal(t, 2, cred.Calls())
+}
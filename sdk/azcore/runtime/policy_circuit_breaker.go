@@ -0,0 +1,190 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/log"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const defaultCircuitOpenDuration = 30 * time.Second
+
+// circuitState is the state of a host's circuit.
+type circuitState int
+
+const (
+	// circuitClosed allows all requests
+	circuitClosed circuitState = iota
+	// circuitOpen rejects all requests
+	circuitOpen
+	// circuitHalfOpen allows one trial request
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitClosed:
+		return "closed"
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// tryOutcome is the outcome of a try as seen by the circuit breaker.
+type tryOutcome int
+
+const (
+	trySucceeded tryOutcome = iota
+	tryFailed
+	// tryAbandoned means the try says nothing about the host's health, e.g. the caller cancelled it
+	tryAbandoned
+)
+
+// circuit tracks the health of a host.
+type circuit struct {
+	state    circuitState
+	failures int
+
+	// retryAt is when an open circuit becomes half-open
+	retryAt time.Time
+
+	// trial is true while a half-open circuit's trial request is in flight
+	trial bool
+
+	// generation is incremented by each state change. Tries record the generation they started in,
+	// so outcomes of tries that started before a state change don't affect the new state.
+	generation uint64
+}
+
+func newCircuitBreakerPolicy(o policy.CircuitBreakerOptions) policy.Policy {
+	if o.OpenDuration <= 0 {
+		o.OpenDuration = defaultCircuitOpenDuration
+	}
+	if o.StatusCodes == nil {
+		// NOTE: if you change this list, you MUST update the docs in policy/policy.go
+		o.StatusCodes = []int{
+			http.StatusInternalServerError, // 500
+			http.StatusBadGateway,          // 502
+			http.StatusServiceUnavailable,  // 503
+			http.StatusGatewayTimeout,      // 504
+		}
+	}
+	return &circuitBreakerPolicy{
+		options:  o,
+		circuits: map[string]*circuit{},
+	}
+}
+
+type circuitBreakerPolicy struct {
+	options policy.CircuitBreakerOptions
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+func (p *circuitBreakerPolicy) Do(req *policy.Request) (*http.Response, error) {
+	host := strings.ToLower(req.Raw().URL.Host)
+	generation, err := p.allow(host, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := req.Next()
+	outcome := trySucceeded
+	if err != nil {
+		outcome = tryFailed
+		if errors.Is(err, context.Canceled) {
+			outcome = tryAbandoned
+		}
+	} else if HasStatusCode(resp, p.options.StatusCodes...) {
+		outcome = tryFailed
+	}
+	p.record(host, generation, outcome, time.Now())
+	return resp, err
+}
+
+// allow returns the generation of host's circuit, or a *CircuitOpenError when a request to host must not be sent.
+func (p *circuitBreakerPolicy) allow(host string, now time.Time) (uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c, ok := p.circuits[host]
+	if !ok {
+		c = &circuit{}
+		p.circuits[host] = c
+	}
+	switch c.state {
+	case circuitOpen:
+		if now.Before(c.retryAt) {
+			return 0, &exported.CircuitOpenError{Host: host, RetryAfter: c.retryAt}
+		}
+		p.transition(host, c, circuitHalfOpen)
+		c.trial = true
+	case circuitHalfOpen:
+		if c.trial {
+			return 0, &exported.CircuitOpenError{Host: host, RetryAfter: c.retryAt}
+		}
+		c.trial = true
+	}
+	return c.generation, nil
+}
+
+// record updates the circuit for host with the outcome of a try that started in the specified generation.
+func (p *circuitBreakerPolicy) record(host string, generation uint64, outcome tryOutcome, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c := p.circuits[host]
+	if generation != c.generation {
+		// the try started before the circuit's last state change, so its outcome is stale
+		return
+	}
+	switch c.state {
+	case circuitClosed:
+		switch outcome {
+		case trySucceeded:
+			c.failures = 0
+		case tryFailed:
+			c.failures++
+			if c.failures >= p.options.FailureThreshold {
+				c.retryAt = now.Add(p.options.OpenDuration)
+				p.transition(host, c, circuitOpen)
+			}
+		}
+	case circuitHalfOpen:
+		switch outcome {
+		case trySucceeded:
+			c.failures = 0
+			p.transition(host, c, circuitClosed)
+		case tryFailed:
+			c.retryAt = now.Add(p.options.OpenDuration)
+			p.transition(host, c, circuitOpen)
+		}
+		c.trial = false
+	}
+}
+
+// transition changes the state of c. Must be called with the lock held.
+func (p *circuitBreakerPolicy) transition(host string, c *circuit, state circuitState) {
+	if state == circuitOpen {
+		log.Writef(log.EventCircuitBreaker, "circuit for host %s changed from %s to %s after %d consecutive failures, retry after %s",
+			host, c.state, state, c.failures, c.retryAt.Format(time.RFC3339))
+	} else {
+		log.Writef(log.EventCircuitBreaker, "circuit for host %s changed from %s to %s", host, c.state, state)
+	}
+	c.state = state
+	c.generation++
+}
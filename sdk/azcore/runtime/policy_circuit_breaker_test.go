@@ -0,0 +1,181 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/log"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreakerPolicy(t *testing.T) {
+	var transitions []string
+	log.SetListener(func(cls log.Event, msg string) {
+		if cls == log.EventCircuitBreaker {
+			transitions = append(transitions, msg)
+		}
+	})
+	defer log.SetListener(nil)
+
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithStatusCode(http.StatusServiceUnavailable))
+	srv.AppendResponse(mock.WithStatusCode(http.StatusServiceUnavailable))
+	srv.AppendResponse(mock.WithStatusCode(http.StatusOK))
+
+	const openDuration = 100 * time.Millisecond
+	pl := NewPipeline("testmodule", "v0.1.0", PipelineOptions{}, &policy.ClientOptions{
+		CircuitBreaker: policy.CircuitBreakerOptions{
+			FailureThreshold: 2,
+			OpenDuration:     openDuration,
+		},
+		Retry: policy.RetryOptions{
+			RetryDelay: time.Millisecond,
+		},
+		Transport: srv,
+	})
+
+	// the second failed try opens the circuit, so the retry policy doesn't send the third
+	req, err := NewRequest(context.Background(), http.MethodGet, srv.URL())
+	require.NoError(t, err)
+	resp, err := pl.Do(req)
+	require.Nil(t, resp)
+	var coe *exported.CircuitOpenError
+	require.True(t, errors.As(err, &coe))
+	require.Equal(t, req.Raw().URL.Host, coe.Host)
+	require.True(t, coe.RetryAfter.After(time.Now()))
+	require.Equal(t, 2, srv.Requests())
+	require.Len(t, transitions, 1)
+	require.Contains(t, transitions[0], "from closed to open")
+
+	// requests fail fast while the circuit is open
+	_, err = pl.Do(req)
+	require.True(t, errors.As(err, &coe))
+	require.Equal(t, 2, srv.Requests())
+
+	// after the open duration, a successful trial closes the circuit
+	time.Sleep(openDuration)
+	resp, err = pl.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 3, srv.Requests())
+	require.Len(t, transitions, 3)
+	require.Contains(t, transitions[1], "from open to half-open")
+	require.Contains(t, transitions[2], "from half-open to closed")
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	const host = "localhost"
+	p := newCircuitBreakerPolicy(policy.CircuitBreakerOptions{FailureThreshold: 1}).(*circuitBreakerPolicy)
+	now := time.Now()
+	gen, err := p.allow(host, now)
+	require.NoError(t, err)
+	p.record(host, gen, tryFailed, now)
+	var coe *exported.CircuitOpenError
+	_, err = p.allow(host, now)
+	require.ErrorAs(t, err, &coe)
+	require.Equal(t, now.Add(defaultCircuitOpenDuration), coe.RetryAfter)
+
+	// half-open allows one trial at a time
+	now = now.Add(defaultCircuitOpenDuration)
+	gen, err = p.allow(host, now)
+	require.NoError(t, err)
+	_, err = p.allow(host, now)
+	require.ErrorAs(t, err, &coe)
+
+	// an abandoned trial allows another
+	p.record(host, gen, tryAbandoned, now)
+	gen, err = p.allow(host, now)
+	require.NoError(t, err)
+
+	// a failed trial opens the circuit again
+	p.record(host, gen, tryFailed, now)
+	_, err = p.allow(host, now)
+	require.ErrorAs(t, err, &coe)
+	require.Equal(t, now.Add(defaultCircuitOpenDuration), coe.RetryAfter)
+
+	// circuits are per host
+	_, err = p.allow("otherhost", now)
+	require.NoError(t, err)
+}
+
+func TestCircuitBreakerIgnoresStaleOutcomes(t *testing.T) {
+	const host = "localhost"
+	p := newCircuitBreakerPolicy(policy.CircuitBreakerOptions{FailureThreshold: 1}).(*circuitBreakerPolicy)
+	now := time.Now()
+	// two tries start while the circuit is closed; the first one's failure opens it
+	gen1, err := p.allow(host, now)
+	require.NoError(t, err)
+	gen2, err := p.allow(host, now)
+	require.NoError(t, err)
+	p.record(host, gen1, tryFailed, now)
+
+	// the circuit becomes half-open and allows a trial
+	now = now.Add(defaultCircuitOpenDuration)
+	trial, err := p.allow(host, now)
+	require.NoError(t, err)
+
+	// the second try's late outcome doesn't end the trial
+	p.record(host, gen2, trySucceeded, now)
+	var coe *exported.CircuitOpenError
+	_, err = p.allow(host, now)
+	require.ErrorAs(t, err, &coe)
+	require.Equal(t, circuitHalfOpen, p.circuits[host].state)
+
+	// the trial's outcome does
+	p.record(host, trial, trySucceeded, now)
+	require.Equal(t, circuitClosed, p.circuits[host].state)
+	_, err = p.allow(host, now)
+	require.NoError(t, err)
+}
+
+func TestCircuitBreakerSuccessResetsFailures(t *testing.T) {
+	const threshold = 3
+	srv, close := mock.NewServer()
+	defer close()
+	pl := NewPipeline("testmodule", "v0.1.0", PipelineOptions{}, &policy.ClientOptions{
+		CircuitBreaker: policy.CircuitBreakerOptions{FailureThreshold: threshold},
+		Retry:          policy.RetryOptions{MaxRetries: -1},
+		Transport:      srv,
+	})
+	req, err := NewRequest(context.Background(), http.MethodGet, srv.URL())
+	require.NoError(t, err)
+
+	fail := func() {
+		for i := 0; i < threshold-1; i++ {
+			srv.AppendResponse(mock.WithStatusCode(http.StatusServiceUnavailable))
+			resp, err := pl.Do(req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		}
+	}
+
+	fail()
+	srv.AppendResponse(mock.WithStatusCode(http.StatusOK))
+	resp, err := pl.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// the success reset the count, so the circuit stays closed after another threshold-1 failures
+	fail()
+	requests := srv.Requests()
+	srv.AppendResponse(mock.WithStatusCode(http.StatusOK))
+	resp, err = pl.Do(req)
+	var coe *exported.CircuitOpenError
+	require.False(t, errors.As(err, &coe))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, requests+1, srv.Requests())
+}
@@ -0,0 +1,186 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/log"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const (
+	// hedgingLatencySamples is the number of recent latencies used to compute the percentile delay
+	hedgingLatencySamples = 100
+
+	// hedgingMinLatencySamples is the number of latencies required before using the percentile delay
+	hedgingMinLatencySamples = 10
+)
+
+func newHedgingPolicy(o policy.HedgingOptions) policy.Policy {
+	return &hedgingPolicy{options: o}
+}
+
+type hedgingPolicy struct {
+	options policy.HedgingOptions
+
+	// latencies is a ring buffer of recent latencies, used when options.Percentile is set
+	mu        sync.Mutex
+	latencies []time.Duration
+	next      int
+}
+
+// hedgeResult is the outcome of a hedged attempt.
+type hedgeResult struct {
+	attempt int
+	resp    *http.Response
+	err     error
+	cancel  context.CancelFunc
+	start   time.Time
+}
+
+func (p *hedgingPolicy) Do(req *policy.Request) (*http.Response, error) {
+	switch req.Raw().Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return req.Next()
+	}
+	delay := p.delay()
+	if delay <= 0 {
+		start := time.Now()
+		resp, err := req.Next()
+		if err == nil {
+			p.record(time.Since(start))
+		}
+		return resp, err
+	}
+
+	// attempts can't share the request body because they're sent concurrently and
+	// policies rewind the body, so each attempt reads from its own copy of it
+	var body []byte
+	if req.Body() != nil {
+		if err := req.RewindBody(); err != nil {
+			return nil, err
+		}
+		var err error
+		if body, err = io.ReadAll(req.Body()); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make(chan hedgeResult, 2)
+	var cancels []context.CancelFunc
+	send := func() {
+		ctx, cancel := context.WithCancel(req.Raw().Context())
+		cancels = append(cancels, cancel)
+		result := hedgeResult{attempt: len(cancels) - 1, cancel: cancel}
+		go func() {
+			clone := exported.IsolatedClone(req, ctx)
+			result.start = time.Now()
+			if body != nil {
+				result.err = clone.SetBody(shared.NewNopClosingBytesReader(body), req.Raw().Header.Get(shared.HeaderContentType))
+			}
+			if result.err == nil {
+				result.resp, result.err = clone.Next()
+			}
+			results <- result
+		}()
+	}
+
+	send()
+	pending := 1
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	var result hedgeResult
+	for {
+		select {
+		case <-timer.C:
+			log.Writef(log.EventHedging, "sending hedged request after %s", delay)
+			send()
+			pending++
+			continue
+		case result = <-results:
+			pending--
+		}
+		if result.err == nil || pending == 0 {
+			break
+		}
+		// this attempt failed but the other one is still in flight, wait for it
+		result.cancel()
+	}
+
+	if pending > 0 {
+		// cancel the losing attempt and clean up after it
+		for i, cancel := range cancels {
+			if i != result.attempt {
+				cancel()
+			}
+		}
+		go func() {
+			if loser := <-results; loser.resp != nil {
+				Drain(loser.resp)
+			}
+		}()
+	}
+	if result.err != nil {
+		result.cancel()
+		return nil, result.err
+	}
+	p.record(time.Since(result.start))
+	if _, ok := result.resp.Body.(*shared.NopClosingBytesReader); ok || result.resp.Body == nil {
+		// the body was already downloaded
+		result.cancel()
+	} else {
+		// must cancel the context after the body has been read and closed
+		result.resp.Body = &contextCancelReadCloser{cf: result.cancel, body: result.resp.Body}
+	}
+	return result.resp, nil
+}
+
+// delay returns how long to wait for a response before sending the hedged request.
+// It returns zero when hedging is disabled.
+func (p *hedgingPolicy) delay() time.Duration {
+	if p.options.Percentile <= 0 || p.options.Percentile >= 100 {
+		return p.options.Delay
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.latencies) < hedgingMinLatencySamples {
+		return p.options.Delay
+	}
+	sorted := make([]time.Duration, len(p.latencies))
+	copy(sorted, p.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	i := int(math.Ceil(p.options.Percentile/100*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+// record adds the latency of a successful response to the recent latencies.
+func (p *hedgingPolicy) record(latency time.Duration) {
+	if p.options.Percentile <= 0 || p.options.Percentile >= 100 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.latencies) < hedgingLatencySamples {
+		p.latencies = append(p.latencies, latency)
+		return
+	}
+	p.latencies[p.next] = latency
+	p.next = (p.next + 1) % hedgingLatencySamples
+}
@@ -0,0 +1,194 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/log"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/stretchr/testify/require"
+)
+
+// newHedgingTestPipeline creates a pipeline whose transport calls do with the number of the attempt
+func newHedgingTestPipeline(t *testing.T, o policy.HedgingOptions, do func(req *http.Request, attempt int32) (*http.Response, error)) (Pipeline, *int32) {
+	attempts := int32(0)
+	pl := NewPipeline("testmodule", "v0.1.0", PipelineOptions{}, &policy.ClientOptions{
+		Hedging: o,
+		Logging: policy.LogOptions{IncludeBody: true},
+		Retry:   policy.RetryOptions{MaxRetries: -1},
+		Transport: shared.TransportFunc(func(req *http.Request) (*http.Response, error) {
+			return do(req, atomic.AddInt32(&attempts, 1))
+		}),
+	})
+	return pl, &attempts
+}
+
+func newHedgingTestResponse(req *http.Request, body string) *http.Response {
+	return &http.Response{
+		Request:    req,
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestHedgingPolicyNoHedge(t *testing.T) {
+	pl, attempts := newHedgingTestPipeline(t, policy.HedgingOptions{Delay: time.Hour}, func(req *http.Request, attempt int32) (*http.Response, error) {
+		return newHedgingTestResponse(req, "fast"), nil
+	})
+	req, err := NewRequest(context.Background(), http.MethodGet, "https://localhost")
+	require.NoError(t, err)
+	resp, err := pl.Do(req)
+	require.NoError(t, err)
+	body, err := Payload(resp)
+	require.NoError(t, err)
+	require.Equal(t, "fast", string(body))
+	require.EqualValues(t, 1, atomic.LoadInt32(attempts))
+}
+
+func TestHedgingPolicyLogging(t *testing.T) {
+	var hedges []string
+	log.SetListener(func(cls log.Event, msg string) {
+		if cls == log.EventHedging {
+			hedges = append(hedges, msg)
+		}
+	})
+	defer log.SetListener(nil)
+
+	// no log policy, so the losing attempt doesn't write log entries after the pipeline returns
+	loserDone := make(chan struct{})
+	attempts := int32(0)
+	pl := exported.NewPipeline(shared.TransportFunc(func(req *http.Request) (*http.Response, error) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			<-req.Context().Done()
+			close(loserDone)
+			return nil, req.Context().Err()
+		}
+		return newHedgingTestResponse(req, "hedged"), nil
+	}), newHedgingPolicy(policy.HedgingOptions{Delay: 10 * time.Millisecond}))
+	req, err := NewRequest(context.Background(), http.MethodGet, "https://localhost")
+	require.NoError(t, err)
+	_, err = pl.Do(req)
+	require.NoError(t, err)
+	<-loserDone
+	require.Len(t, hedges, 1)
+	require.Contains(t, hedges[0], "sending hedged request")
+}
+
+func TestHedgingPolicyHedgedWins(t *testing.T) {
+	loserCancelled := make(chan struct{})
+	pl, attempts := newHedgingTestPipeline(t, policy.HedgingOptions{Delay: 10 * time.Millisecond}, func(req *http.Request, attempt int32) (*http.Response, error) {
+		if attempt == 1 {
+			// the first attempt is slow, so the policy should send a hedged request and cancel this one
+			<-req.Context().Done()
+			close(loserCancelled)
+			return nil, req.Context().Err()
+		}
+		return newHedgingTestResponse(req, "hedged"), nil
+	})
+	req, err := NewRequest(context.Background(), http.MethodGet, "https://localhost")
+	require.NoError(t, err)
+	resp, err := pl.Do(req)
+	require.NoError(t, err)
+	body, err := Payload(resp)
+	require.NoError(t, err)
+	require.Equal(t, "hedged", string(body))
+	require.EqualValues(t, 2, atomic.LoadInt32(attempts))
+	select {
+	case <-loserCancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the losing attempt wasn't cancelled")
+	}
+}
+
+func TestHedgingPolicyRequestBody(t *testing.T) {
+	const content = `{"query":"*"}`
+	pl, attempts := newHedgingTestPipeline(t, policy.HedgingOptions{Delay: 10 * time.Millisecond}, func(req *http.Request, attempt int32) (*http.Response, error) {
+		// each attempt must send the complete body, even though the log policy reads and rewinds it
+		if b, err := io.ReadAll(req.Body); err != nil {
+			return nil, err
+		} else if string(b) != content {
+			return nil, fmt.Errorf("unexpected body %q", b)
+		}
+		if attempt == 1 {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}
+		return newHedgingTestResponse(req, "ok"), nil
+	})
+	req, err := NewRequest(context.Background(), http.MethodGet, "https://localhost")
+	require.NoError(t, err)
+	require.NoError(t, req.SetBody(streaming.NopCloser(strings.NewReader(content)), shared.ContentTypeAppJSON))
+	resp, err := pl.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.EqualValues(t, 2, atomic.LoadInt32(attempts))
+}
+
+func TestHedgingPolicyFailedAttempt(t *testing.T) {
+	expected := errors.New("failed")
+	pl, attempts := newHedgingTestPipeline(t, policy.HedgingOptions{Delay: time.Hour}, func(req *http.Request, attempt int32) (*http.Response, error) {
+		return nil, expected
+	})
+	req, err := NewRequest(context.Background(), http.MethodGet, "https://localhost")
+	require.NoError(t, err)
+	// the attempt fails before the hedging delay, so there's no hedged request
+	_, err = pl.Do(req)
+	require.ErrorIs(t, err, expected)
+	require.EqualValues(t, 1, atomic.LoadInt32(attempts))
+}
+
+func TestHedgingPolicyNotIdempotent(t *testing.T) {
+	pl, attempts := newHedgingTestPipeline(t, policy.HedgingOptions{Delay: time.Millisecond}, func(req *http.Request, attempt int32) (*http.Response, error) {
+		time.Sleep(20 * time.Millisecond)
+		return newHedgingTestResponse(req, ""), nil
+	})
+	for _, method := range []string{http.MethodPatch, http.MethodPost, http.MethodPut, http.MethodDelete} {
+		atomic.StoreInt32(attempts, 0)
+		req, err := NewRequest(context.Background(), method, "https://localhost")
+		require.NoError(t, err)
+		_, err = pl.Do(req)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, atomic.LoadInt32(attempts), method)
+	}
+}
+
+func TestHedgingPolicyPercentileDelay(t *testing.T) {
+	p := newHedgingPolicy(policy.HedgingOptions{Delay: time.Second, Percentile: 90}).(*hedgingPolicy)
+	// Delay is used until there are enough samples
+	for i := 1; i < hedgingMinLatencySamples; i++ {
+		p.record(time.Duration(i) * time.Millisecond)
+		require.Equal(t, time.Second, p.delay())
+	}
+	p.record(hedgingMinLatencySamples * time.Millisecond)
+	require.Equal(t, 9*time.Millisecond, p.delay())
+
+	// only recent samples count
+	for i := 0; i < hedgingLatencySamples; i++ {
+		p.record(time.Minute)
+	}
+	require.Len(t, p.latencies, hedgingLatencySamples)
+	require.Equal(t, time.Minute, p.delay())
+
+	// without a percentile, the delay is constant
+	p = newHedgingPolicy(policy.HedgingOptions{Delay: time.Second}).(*hedgingPolicy)
+	p.record(time.Millisecond)
+	require.Empty(t, p.latencies)
+	require.Equal(t, time.Second, p.delay())
+}
@@ -0,0 +1,224 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/tracing"
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type testSpan struct {
+	name       string
+	parent     *testSpan
+	kind       tracing.SpanKind
+	attributes map[string]any
+	status     tracing.SpanStatus
+	desc       string
+	errs       []error
+	ended      bool
+}
+
+type ctxTestSpan struct{}
+
+// newTestTracer returns a tracer that records all created spans.
+// The traceparent header is set to the name of the active span.
+func newTestTracer(spans *[]*testSpan) tracing.Tracer {
+	return tracing.NewTracer(func(ctx context.Context, spanName string, options *tracing.SpanOptions) (context.Context, tracing.Span) {
+		sp := &testSpan{name: spanName, attributes: map[string]any{}}
+		if parent, ok := ctx.Value(ctxTestSpan{}).(*testSpan); ok {
+			sp.parent = parent
+		}
+		if options != nil {
+			sp.kind = options.Kind
+			for _, attr := range options.Attributes {
+				sp.attributes[attr.Key] = attr.Value
+			}
+		}
+		*spans = append(*spans, sp)
+		return context.WithValue(ctx, ctxTestSpan{}, sp), tracing.NewSpan(tracing.SpanImpl{
+			End: func() { sp.ended = true },
+			SetAttributes: func(attrs ...tracing.Attribute) {
+				for _, attr := range attrs {
+					sp.attributes[attr.Key] = attr.Value
+				}
+			},
+			AddError: func(err error) { sp.errs = append(sp.errs, err) },
+			SetStatus: func(code tracing.SpanStatus, desc string) {
+				sp.status = code
+				sp.desc = desc
+			},
+		})
+	}, &tracing.TracerOptions{
+		Inject: func(ctx context.Context, header http.Header) {
+			if sp, ok := ctx.Value(ctxTestSpan{}).(*testSpan); ok {
+				header.Set("traceparent", sp.name)
+			}
+		},
+	})
+}
+
+func TestHTTPTracePolicy(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+
+	var spans []*testSpan
+	pl := exported.NewPipeline(srv, newHTTPTracePolicy(newTestTracer(&spans), nil))
+
+	// no tracer
+	srv.AppendResponse()
+	req, err := NewRequest(context.Background(), http.MethodGet, srv.URL())
+	require.NoError(t, err)
+	_, err = exported.NewPipeline(srv, newHTTPTracePolicy(tracing.Tracer{}, nil)).Do(req)
+	require.NoError(t, err)
+	require.Empty(t, spans)
+
+	// success
+	srv.AppendResponse(mock.WithHeader(shared.HeaderXMSRequestID, "service-id"))
+	req, err = NewRequest(context.Background(), http.MethodGet, srv.URL()+"?foo=bar&api-version=1")
+	require.NoError(t, err)
+	req.Raw().Header.Set(shared.HeaderUserAgent, "my-user-agent")
+	req.Raw().Header.Set(shared.HeaderXMSClientRequestID, "client-id")
+	resp, err := pl.Do(req)
+	require.NoError(t, err)
+	require.Len(t, spans, 1)
+	sp := spans[0]
+	require.Equal(t, "HTTP GET", sp.name)
+	require.Equal(t, tracing.SpanKindClient, sp.kind)
+	require.True(t, sp.ended)
+	require.Equal(t, tracing.SpanStatusUnset, sp.status)
+	require.Equal(t, http.MethodGet, sp.attributes[attrHTTPMethod])
+	require.Equal(t, srv.URL()+"?api-version=1&foo=REDACTED", sp.attributes[attrHTTPURL])
+	require.Equal(t, req.Raw().URL.Host, sp.attributes[attrNetPeerName])
+	require.Equal(t, "my-user-agent", sp.attributes[attrHTTPUserAgent])
+	require.Equal(t, "client-id", sp.attributes[attrAZClientReqID])
+	require.Equal(t, "service-id", sp.attributes[attrAZServiceReqID])
+	require.Equal(t, http.StatusOK, sp.attributes[attrHTTPStatusCode])
+	require.NotContains(t, sp.attributes, attrHTTPResendCount)
+	require.Equal(t, "HTTP GET", resp.Request.Header.Get("traceparent"))
+	// the caller's request must not be modified
+	require.Empty(t, req.Raw().Header.Get("traceparent"))
+
+	// failure status code
+	spans = nil
+	srv.AppendResponse(mock.WithStatusCode(http.StatusBadRequest))
+	req, err = NewRequest(context.Background(), http.MethodPut, srv.URL())
+	require.NoError(t, err)
+	_, err = pl.Do(req)
+	require.NoError(t, err)
+	require.Len(t, spans, 1)
+	require.Equal(t, "HTTP PUT", spans[0].name)
+	require.Equal(t, tracing.SpanStatusError, spans[0].status)
+	require.Equal(t, "400 Bad Request", spans[0].desc)
+	require.Equal(t, http.StatusBadRequest, spans[0].attributes[attrHTTPStatusCode])
+
+	// transport failure, the URL must not be included in the error
+	spans = nil
+	srv.AppendError(&net.OpError{Op: "read", Err: errors.New("connection reset")})
+	req, err = NewRequest(context.Background(), http.MethodGet, srv.URL()+"?secret=value")
+	require.NoError(t, err)
+	_, err = pl.Do(req)
+	require.Error(t, err)
+	require.Len(t, spans, 1)
+	require.Equal(t, tracing.SpanStatusError, spans[0].status)
+	require.NotContains(t, spans[0].desc, "secret")
+	require.Len(t, spans[0].errs, 1)
+	require.True(t, spans[0].ended)
+}
+
+func TestHTTPTracePolicyRetries(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithStatusCode(http.StatusServiceUnavailable))
+	srv.AppendResponse(mock.WithStatusCode(http.StatusServiceUnavailable))
+	srv.AppendResponse()
+
+	var spans []*testSpan
+	pl := exported.NewPipeline(srv, NewRetryPolicy(testRetryOptions()), newHTTPTracePolicy(newTestTracer(&spans), nil))
+	req, err := NewRequest(context.Background(), http.MethodGet, srv.URL())
+	require.NoError(t, err)
+	resp, err := pl.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, spans, 3)
+	require.NotContains(t, spans[0].attributes, attrHTTPResendCount)
+	require.Equal(t, 1, spans[1].attributes[attrHTTPResendCount])
+	require.Equal(t, 2, spans[2].attributes[attrHTTPResendCount])
+	for _, sp := range spans {
+		require.True(t, sp.ended)
+	}
+}
+
+func TestNewPipelineTracing(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.SetResponse()
+
+	var pipelineSpans []*testSpan
+	provider := tracing.NewProvider(func(name, version string) tracing.Tracer {
+		require.Equal(t, "test", name)
+		require.Equal(t, "v1.2.3", version)
+		return newTestTracer(&pipelineSpans)
+	}, nil)
+	pl := NewPipeline("test", "v1.2.3", PipelineOptions{}, &policy.ClientOptions{Transport: srv, TracingProvider: provider})
+
+	// without an operation span the pipeline's tracer creates a root span
+	req, err := NewRequest(context.Background(), http.MethodGet, srv.URL())
+	require.NoError(t, err)
+	resp, err := pl.Do(req)
+	require.NoError(t, err)
+	require.Len(t, pipelineSpans, 1)
+	require.Nil(t, pipelineSpans[0].parent)
+	require.True(t, strings.HasPrefix(pipelineSpans[0].attributes[attrHTTPUserAgent].(string), "azsdk-go-test/v1.2.3"))
+	require.Equal(t, "HTTP GET", resp.Request.Header.Get("traceparent"))
+
+	// with an operation span the HTTP span is created by the client's tracer and nested
+	var clientSpans []*testSpan
+	ctx, endSpan := StartSpan(context.Background(), "Client.Method", newTestTracer(&clientSpans), nil)
+	req, err = NewRequest(ctx, http.MethodGet, srv.URL())
+	require.NoError(t, err)
+	_, err = pl.Do(req)
+	require.NoError(t, err)
+	endSpan(errors.New("failed"))
+	require.Len(t, pipelineSpans, 1)
+	require.Len(t, clientSpans, 2)
+	require.Equal(t, "Client.Method", clientSpans[0].name)
+	require.Equal(t, tracing.SpanKindInternal, clientSpans[0].kind)
+	require.True(t, clientSpans[0].ended)
+	require.Equal(t, tracing.SpanStatusError, clientSpans[0].status)
+	require.Equal(t, "HTTP GET", clientSpans[1].name)
+	require.Same(t, clientSpans[0], clientSpans[1].parent)
+}
+
+func TestStartSpan(t *testing.T) {
+	// no tracer
+	ctx, endSpan := StartSpan(context.Background(), "Client.Method", tracing.Tracer{}, nil)
+	require.Equal(t, context.Background(), ctx)
+	endSpan(nil)
+
+	var spans []*testSpan
+	tr := newTestTracer(&spans)
+	ctx, endOuter := StartSpan(context.Background(), "Client.Outer", tr, nil)
+	// nested SDK calls are suppressed
+	_, endInner := StartSpan(ctx, "Client.Inner", tr, nil)
+	endInner(nil)
+	endOuter(nil)
+	require.Len(t, spans, 1)
+	require.Equal(t, "Client.Outer", spans[0].name)
+	require.True(t, spans[0].ended)
+	require.Equal(t, tracing.SpanStatusUnset, spans[0].status)
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"sort"
 	"strings"
 	"time"
@@ -65,18 +66,23 @@ func NewLogPolicy(o *policy.LogOptions) policy.Policy {
 	for _, ah := range o.AllowedHeaders {
 		allowedHeaders[strings.ToLower(ah)] = struct{}{}
 	}
-	// now do the same thing for query params
+	return &logPolicy{
+		includeBody:    o.IncludeBody,
+		allowedHeaders: allowedHeaders,
+		allowedQP:      getAllowedQueryParams(o.AllowedQueryParams),
+	}
+}
+
+// getAllowedQueryParams merges the default set of allowed query parameters
+// with a custom set (usually comes from client options).
+func getAllowedQueryParams(customAllowedQP []string) map[string]struct{} {
 	allowedQP := map[string]struct{}{
 		"api-version": {},
 	}
-	for _, qp := range o.AllowedQueryParams {
+	for _, qp := range customAllowedQP {
 		allowedQP[strings.ToLower(qp)] = struct{}{}
 	}
-	return &logPolicy{
-		includeBody:    o.IncludeBody,
-		allowedHeaders: allowedHeaders,
-		allowedQP:      allowedQP,
-	}
+	return allowedQP
 }
 
 // logPolicyOpValues is the struct containing the per-operation values
@@ -108,6 +114,14 @@ func (p *logPolicy) Do(req *policy.Request) (*http.Response, error) {
 			return nil, err
 		}
 	}
+	if log.ShouldRecord(log.EventRequest) {
+		log.Record(log.EventRequest, log.LevelInfo, "outgoing request",
+			log.Attr{Key: "method", Value: req.Raw().Method},
+			log.Attr{Key: "url", Value: getSanitizedURL(*req.Raw().URL, p.allowedQP)},
+			log.Attr{Key: "try", Value: opValues.try},
+			log.Attr{Key: "client_request_id", Value: req.Raw().Header.Get(shared.HeaderXMSClientRequestID)},
+		)
+	}
 
 	// Set the time for this particular retry operation and then Do the operation.
 	tryStart := time.Now()
@@ -115,6 +129,7 @@ func (p *logPolicy) Do(req *policy.Request) (*http.Response, error) {
 	tryEnd := time.Now()
 	tryDuration := tryEnd.Sub(tryStart)
 	opDuration := tryEnd.Sub(opValues.start)
+	getPipelineMetrics(req).tryDuration.Record(req.Raw().Context(), float64(tryDuration)/float64(time.Millisecond), httpMetricAttributes(req, response, err)...)
 
 	if log.Should(log.EventResponse) {
 		// We're going to log this; build the string to log
@@ -135,6 +150,30 @@ func (p *logPolicy) Do(req *policy.Request) (*http.Response, error) {
 		}
 		log.Write(log.EventResponse, b.String())
 	}
+	if log.ShouldRecord(log.EventResponse) {
+		attrs := []log.Attr{
+			{Key: "method", Value: req.Raw().Method},
+			{Key: "url", Value: getSanitizedURL(*req.Raw().URL, p.allowedQP)},
+			{Key: "try", Value: opValues.try},
+			{Key: "duration", Value: tryDuration},
+			{Key: "operation_duration", Value: opDuration},
+			{Key: "client_request_id", Value: req.Raw().Header.Get(shared.HeaderXMSClientRequestID)},
+		}
+		if err != nil {
+			attrs = append(attrs, log.Attr{Key: "error", Value: err.Error()})
+			log.Record(log.EventResponse, log.LevelError, "request error", attrs...)
+		} else {
+			attrs = append(attrs,
+				log.Attr{Key: "status_code", Value: response.StatusCode},
+				log.Attr{Key: "request_id", Value: response.Header.Get(shared.HeaderXMSRequestID)},
+			)
+			level := log.LevelInfo
+			if response.StatusCode >= http.StatusBadRequest {
+				level = log.LevelWarn
+			}
+			log.Record(log.EventResponse, level, "response received", attrs...)
+		}
+	}
 	return response, err
 }
 
@@ -143,17 +182,8 @@ const redactedValue = "REDACTED"
 // writeRequestWithResponse appends a formatted HTTP request into a Buffer. If request and/or err are
 // not nil, then these are also written into the Buffer.
 func (p *logPolicy) writeRequestWithResponse(b *bytes.Buffer, req *policy.Request, resp *http.Response, err error) {
-	// redact applicable query params
-	cpURL := *req.Raw().URL
-	qp := cpURL.Query()
-	for k := range qp {
-		if _, ok := p.allowedQP[strings.ToLower(k)]; !ok {
-			qp.Set(k, redactedValue)
-		}
-	}
-	cpURL.RawQuery = qp.Encode()
 	// Write the request into the buffer.
-	fmt.Fprint(b, "   "+req.Raw().Method+" "+cpURL.String()+"\n")
+	fmt.Fprint(b, "   "+req.Raw().Method+" "+getSanitizedURL(*req.Raw().URL, p.allowedQP)+"\n")
 	p.writeHeader(b, req.Raw().Header)
 	if resp != nil {
 		fmt.Fprintln(b, "   --------------------------------------------------------------------------------")
@@ -166,6 +196,19 @@ func (p *logPolicy) writeRequestWithResponse(b *bytes.Buffer, req *policy.Reques
 	}
 }
 
+// getSanitizedURL returns a sanitized string for the provided url.URL
+func getSanitizedURL(u url.URL, allowedQueryParams map[string]struct{}) string {
+	// redact applicable query params
+	qp := u.Query()
+	for k := range qp {
+		if _, ok := allowedQueryParams[strings.ToLower(k)]; !ok {
+			qp.Set(k, redactedValue)
+		}
+	}
+	u.RawQuery = qp.Encode()
+	return u.String()
+}
+
 // formatHeaders appends an HTTP request's or response's header into a Buffer.
 func (p *logPolicy) writeHeader(b *bytes.Buffer, header http.Header) {
 	if len(header) == 0 {
@@ -14,10 +14,12 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/log"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	azlog "github.com/Azure/azure-sdk-for-go/sdk/azcore/log"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/mock"
 	"github.com/stretchr/testify/require"
@@ -162,6 +164,7 @@ func TestWithAllowedHeadersQueryParams(t *testing.T) {
 	log.SetListener(func(cls log.Event, s string) {
 		rawlog[cls] = s
 	})
+	defer log.SetListener(nil)
 
 	const (
 		plAllowedHeader = "pipeline-allowed"
@@ -307,3 +310,58 @@ func TestWriteRespBodyReadError(t *testing.T) {
 	require.Error(t, writeRespBody(resp, &buf))
 	require.Contains(t, buf.String(), "Failed to read response body: read failed")
 }
+
+func TestPolicyLoggingRecords(t *testing.T) {
+	var records []azlog.Record
+	azlog.SetRecordListener(func(r azlog.Record) {
+		records = append(records, r)
+	})
+	defer azlog.SetRecordListener(nil)
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithStatusCode(http.StatusServiceUnavailable))
+	srv.AppendResponse(mock.WithStatusCode(http.StatusOK), mock.WithHeader(shared.HeaderXMSRequestID, "request-id"))
+	pl := exported.NewPipeline(srv, NewRetryPolicy(&policy.RetryOptions{RetryDelay: time.Millisecond}), NewLogPolicy(nil))
+	req, err := NewRequest(context.Background(), http.MethodGet, srv.URL()+"?sig=secret")
+	require.NoError(t, err)
+	resp, err := pl.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	attrs := func(r azlog.Record) map[string]interface{} {
+		m := map[string]interface{}{}
+		for _, a := range r.Attrs {
+			m[a.Key] = a.Value
+		}
+		return m
+	}
+	var responses, retries []azlog.Record
+	for _, r := range records {
+		switch r.Event {
+		case log.EventRequest:
+			require.Equal(t, "outgoing request", r.Message)
+			require.Equal(t, srv.URL()+"?sig=REDACTED", attrs(r)["url"])
+		case log.EventResponse:
+			responses = append(responses, r)
+		case log.EventRetryPolicy:
+			if r.Message == "retrying" {
+				retries = append(retries, r)
+			}
+		}
+	}
+	require.Len(t, responses, 2)
+	require.Equal(t, log.LevelWarn, responses[0].Level)
+	require.Equal(t, http.StatusServiceUnavailable, attrs(responses[0])["status_code"])
+	require.Equal(t, log.LevelInfo, responses[1].Level)
+	a := attrs(responses[1])
+	require.Equal(t, http.StatusOK, a["status_code"])
+	require.Equal(t, "request-id", a["request_id"])
+	require.EqualValues(t, 2, a["try"])
+	require.IsType(t, time.Duration(0), a["duration"])
+
+	require.Len(t, retries, 1)
+	a = attrs(retries[0])
+	require.EqualValues(t, 1, a["try"])
+	require.Equal(t, http.StatusServiceUnavailable, a["status_code"])
+	require.IsType(t, time.Duration(0), a["delay"])
+}
@@ -0,0 +1,133 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/metrics"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const (
+	metricHTTPClientDuration = "http.client.duration"
+	metricOperationDuration  = "az.http.operation.duration"
+	metricOperationTries     = "az.http.operation.tries"
+	metricRetries            = "az.http.retries"
+	metricAuthTokenDuration  = "az.auth.token.duration"
+
+	metricAttrErrorType = "error.type"
+
+	// values of the error.type attribute other than a response error's code
+	metricErrorTypeTimeout  = "timeout"
+	metricErrorTypeCanceled = "canceled"
+	metricErrorTypeOther    = "other"
+)
+
+// pipelineMetrics contains the instruments used by the pipeline's policies.
+// The zero-value is a no-op implementation.
+type pipelineMetrics struct {
+	tryDuration   metrics.Float64Histogram
+	opDuration    metrics.Float64Histogram
+	opTries       metrics.Float64Histogram
+	retries       metrics.Int64Counter
+	tokenDuration metrics.Float64Histogram
+}
+
+func newPipelineMetrics(meter metrics.Meter) pipelineMetrics {
+	return pipelineMetrics{
+		tryDuration: meter.Float64Histogram(metricHTTPClientDuration, &metrics.InstrumentOptions{
+			Description: "Duration of each HTTP request sent by the client.",
+			Unit:        "ms",
+		}),
+		opDuration: meter.Float64Histogram(metricOperationDuration, &metrics.InstrumentOptions{
+			Description: "Duration of each HTTP operation, including retries.",
+			Unit:        "ms",
+		}),
+		opTries: meter.Float64Histogram(metricOperationTries, &metrics.InstrumentOptions{
+			Description: "Number of tries of each HTTP operation.",
+			Unit:        "1",
+		}),
+		retries: meter.Int64Counter(metricRetries, &metrics.InstrumentOptions{
+			Description: "Number of retried HTTP requests.",
+			Unit:        "1",
+		}),
+		tokenDuration: meter.Float64Histogram(metricAuthTokenDuration, &metrics.InstrumentOptions{
+			Description: "Duration of each access token request made by the bearer token policy.",
+			Unit:        "ms",
+		}),
+	}
+}
+
+// getPipelineMetrics returns the instruments added to the request by the metrics policy.
+// If there are none, the returned value is a no-op implementation.
+func getPipelineMetrics(req *policy.Request) pipelineMetrics {
+	var m pipelineMetrics
+	req.OperationValue(&m)
+	return m
+}
+
+// newMetricsPolicy creates a policy that makes the pipeline's instruments available to the policies that follow it.
+func newMetricsPolicy(meter metrics.Meter) policy.Policy {
+	return &metricsPolicy{m: newPipelineMetrics(meter)}
+}
+
+type metricsPolicy struct {
+	m pipelineMetrics
+}
+
+func (p *metricsPolicy) Do(req *policy.Request) (*http.Response, error) {
+	req.SetOperationValue(p.m)
+	return req.Next()
+}
+
+// httpMetricAttributes returns the attributes describing the request and its outcome.
+func httpMetricAttributes(req *policy.Request, resp *http.Response, err error) []metrics.Attribute {
+	attrs := []metrics.Attribute{
+		{Key: attrHTTPMethod, Value: req.Raw().Method},
+		{Key: attrNetPeerName, Value: req.Raw().URL.Host},
+	}
+	if resp != nil {
+		attrs = append(attrs, metrics.Attribute{Key: attrHTTPStatusCode, Value: resp.StatusCode})
+	} else if err != nil {
+		attrs = append(attrs, metrics.Attribute{Key: metricAttrErrorType, Value: metricErrorType(err)})
+	}
+	return attrs
+}
+
+// metricErrorType returns the value of the error.type attribute for err, which is one of
+//   - the error code of an *azcore.ResponseError, or its status code when it has no error code
+//   - "timeout" when a deadline was exceeded or a network operation timed out
+//   - "canceled" when a context was canceled
+//   - "other" for any other error
+func metricErrorType(err error) string {
+	var respErr *exported.ResponseError
+	if errors.As(err, &respErr) {
+		if respErr.ErrorCode != "" {
+			return respErr.ErrorCode
+		}
+		return strconv.Itoa(respErr.StatusCode)
+	}
+	var timeoutErr interface{ Timeout() bool }
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &timeoutErr) && timeoutErr.Timeout()) {
+		return metricErrorTypeTimeout
+	}
+	if errors.Is(err, context.Canceled) {
+		return metricErrorTypeCanceled
+	}
+	return metricErrorTypeOther
+}
+
+// recordDuration records the milliseconds elapsed since start.
+func recordDuration(ctx context.Context, h metrics.Float64Histogram, start time.Time, attrs ...metrics.Attribute) {
+	h.Record(ctx, float64(time.Since(start))/float64(time.Millisecond), attrs...)
+}
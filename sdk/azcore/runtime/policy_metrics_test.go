@@ -0,0 +1,173 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/metrics"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type testMeasurement struct {
+	value float64
+	attrs map[string]any
+}
+
+// testMeter records all measurements by instrument name
+type testMeter struct {
+	mu           sync.Mutex
+	measurements map[string][]testMeasurement
+}
+
+func (tm *testMeter) record(name string, value float64, attrs []metrics.Attribute) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	m := testMeasurement{value: value, attrs: map[string]any{}}
+	for _, attr := range attrs {
+		m.attrs[attr.Key] = attr.Value
+	}
+	tm.measurements[name] = append(tm.measurements[name], m)
+}
+
+func newTestMetricsProvider() (metrics.Provider, *testMeter) {
+	tm := &testMeter{measurements: map[string][]testMeasurement{}}
+	return metrics.NewProvider(func(name, version string) metrics.Meter {
+		return metrics.NewMeter(metrics.MeterImpl{
+			Int64Counter: func(name string, options *metrics.InstrumentOptions) metrics.Int64Counter {
+				return metrics.NewInt64Counter(func(ctx context.Context, incr int64, attrs ...metrics.Attribute) {
+					tm.record(name, float64(incr), attrs)
+				})
+			},
+			Float64Histogram: func(name string, options *metrics.InstrumentOptions) metrics.Float64Histogram {
+				return metrics.NewFloat64Histogram(func(ctx context.Context, value float64, attrs ...metrics.Attribute) {
+					tm.record(name, value, attrs)
+				})
+			},
+		})
+	}, nil), tm
+}
+
+func TestPipelineMetrics(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithStatusCode(http.StatusTooManyRequests))
+	srv.AppendResponse()
+
+	provider, tm := newTestMetricsProvider()
+	pl := NewPipeline("test", "v1.2.3", PipelineOptions{
+		PerRetry: []policy.Policy{NewBearerTokenPolicy(mockCredential{}, []string{scope}, nil)},
+	}, &policy.ClientOptions{
+		MetricsProvider: provider,
+		Retry:           *testRetryOptions(),
+		Transport:       srv,
+	})
+	req, err := NewRequest(context.Background(), http.MethodGet, srv.URL())
+	require.NoError(t, err)
+	resp, err := pl.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	tries := tm.measurements[metricHTTPClientDuration]
+	require.Len(t, tries, 2)
+	require.Equal(t, http.StatusTooManyRequests, tries[0].attrs[attrHTTPStatusCode])
+	require.Equal(t, http.StatusOK, tries[1].attrs[attrHTTPStatusCode])
+	require.Equal(t, http.MethodGet, tries[1].attrs[attrHTTPMethod])
+	require.Equal(t, req.Raw().URL.Host, tries[1].attrs[attrNetPeerName])
+
+	retries := tm.measurements[metricRetries]
+	require.Len(t, retries, 1)
+	require.EqualValues(t, 1, retries[0].value)
+	require.Equal(t, http.StatusTooManyRequests, retries[0].attrs[attrHTTPStatusCode])
+
+	require.Len(t, tm.measurements[metricOperationDuration], 1)
+	opTries := tm.measurements[metricOperationTries]
+	require.Len(t, opTries, 1)
+	require.EqualValues(t, 2, opTries[0].value)
+	require.Equal(t, http.StatusOK, opTries[0].attrs[attrHTTPStatusCode])
+
+	// the token is cached after the first try
+	tokens := tm.measurements[metricAuthTokenDuration]
+	require.Len(t, tokens, 1)
+	require.NotContains(t, tokens[0].attrs, metricAttrErrorType)
+}
+
+func TestPipelineMetricsTokenError(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.SetResponse()
+
+	provider, tm := newTestMetricsProvider()
+	cred := mockCredential{getTokenImpl: func(context.Context, policy.TokenRequestOptions) (exported.AccessToken, error) {
+		return exported.AccessToken{}, errors.New("no token")
+	}}
+	pl := NewPipeline("test", "v1.2.3", PipelineOptions{
+		PerRetry: []policy.Policy{NewBearerTokenPolicy(cred, []string{scope}, nil)},
+	}, &policy.ClientOptions{MetricsProvider: provider, Transport: srv})
+	req, err := NewRequest(context.Background(), http.MethodGet, srv.URL())
+	require.NoError(t, err)
+	_, err = pl.Do(req)
+	require.Error(t, err)
+
+	tokens := tm.measurements[metricAuthTokenDuration]
+	require.Len(t, tokens, 1)
+	require.Equal(t, metricErrorTypeOther, tokens[0].attrs[metricAttrErrorType])
+	require.Empty(t, tm.measurements[metricHTTPClientDuration])
+	opTries := tm.measurements[metricOperationTries]
+	require.Len(t, opTries, 1)
+	require.EqualValues(t, 1, opTries[0].value)
+	require.Equal(t, metricErrorTypeOther, opTries[0].attrs[metricAttrErrorType])
+}
+
+func TestPipelineMetricsDisabled(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.SetResponse()
+	pl := exported.NewPipeline(srv, NewRetryPolicy(nil), NewLogPolicy(nil))
+	req, err := NewRequest(context.Background(), http.MethodGet, srv.URL())
+	require.NoError(t, err)
+	_, err = pl.Do(req)
+	require.NoError(t, err)
+	require.Zero(t, getPipelineMetrics(req))
+}
+
+func TestMetricErrorType(t *testing.T) {
+	newResponseError := func(body string) error {
+		return exported.NewResponseError(&http.Response{
+			StatusCode: http.StatusNotFound,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    &http.Request{Method: http.MethodGet, URL: &url.URL{Scheme: "https", Host: "contoso.com"}},
+		})
+	}
+	for _, test := range []struct {
+		err      error
+		expected string
+	}{
+		{newResponseError(`{"error":{"code":"ResourceNotFound"}}`), "ResourceNotFound"},
+		{fmt.Errorf("wrapped: %w", newResponseError("")), "404"},
+		{context.DeadlineExceeded, metricErrorTypeTimeout},
+		{&net.DNSError{IsTimeout: true}, metricErrorTypeTimeout},
+		{fmt.Errorf("wrapped: %w", context.Canceled), metricErrorTypeCanceled},
+		{&net.DNSError{}, metricErrorTypeOther},
+		{errors.New("failed"), metricErrorTypeOther},
+	} {
+		require.Equal(t, test.expected, metricErrorType(test.err), test.err.Error())
+	}
+}
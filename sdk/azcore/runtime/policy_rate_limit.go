@@ -0,0 +1,52 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/exported"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// newRateLimitPolicy creates a policy that limits the rate of requests sent through its pipeline.
+func newRateLimitPolicy(o policy.RateLimitOptions) policy.Policy {
+	if o.Burst <= 0 {
+		o.Burst = int(math.Ceil(o.RequestsPerSecond))
+		if o.Burst < 1 {
+			o.Burst = 1
+		}
+	}
+	limiter := o.Limiter
+	if limiter == nil {
+		limiter = exported.NewRateLimiter()
+	}
+	return &rateLimitPolicy{options: o, limiter: limiter}
+}
+
+type rateLimitPolicy struct {
+	options policy.RateLimitOptions
+	limiter *exported.RateLimiter
+}
+
+func (p *rateLimitPolicy) Do(req *policy.Request) (*http.Response, error) {
+	b := exported.RateLimiterBucket(p.limiter, req.Raw().URL.Host, p.options.RequestsPerSecond, p.options.Burst)
+	if wait := b.Take(time.Now()); wait > 0 {
+		if err := shared.Delay(req.Raw().Context(), wait); err != nil {
+			b.GiveBack()
+			return nil, err
+		}
+	}
+	resp, err := req.Next()
+	if err == nil {
+		b.Update(resp, time.Now())
+	}
+	return resp, err
+}
@@ -0,0 +1,81 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRateLimitPolicyDefaultBurst(t *testing.T) {
+	p := newRateLimitPolicy(policy.RateLimitOptions{RequestsPerSecond: 0.5})
+	require.Equal(t, 1, p.(*rateLimitPolicy).options.Burst)
+	p = newRateLimitPolicy(policy.RateLimitOptions{RequestsPerSecond: 2.5})
+	require.Equal(t, 3, p.(*rateLimitPolicy).options.Burst)
+}
+
+func TestRateLimitPolicy(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithStatusCode(http.StatusOK), mock.WithHeader("x-ms-ratelimit-remaining-subscription-reads", "0"))
+	srv.AppendResponse(mock.WithStatusCode(http.StatusOK))
+	srv.AppendResponse(mock.WithStatusCode(http.StatusOK))
+
+	limiter := policy.NewRateLimiter()
+	opts := &policy.ClientOptions{
+		RateLimit: policy.RateLimitOptions{RequestsPerSecond: 10, Burst: 10, Limiter: limiter},
+		Transport: srv,
+	}
+	pl1 := NewPipeline("testmodule", "v0.1.0", PipelineOptions{}, opts)
+	pl2 := NewPipeline("testmodule", "v0.1.0", PipelineOptions{}, opts)
+	pl3 := NewPipeline("testmodule", "v0.1.0", PipelineOptions{}, &policy.ClientOptions{
+		RateLimit: policy.RateLimitOptions{RequestsPerSecond: 10, Burst: 10},
+		Transport: srv,
+	})
+
+	req, err := NewRequest(context.Background(), http.MethodGet, srv.URL())
+	require.NoError(t, err)
+	resp, err := pl1.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// the service reported no remaining quota, so the next request to the host must wait for a
+	// token, even though it's sent by another pipeline, because the pipelines share a limiter
+	req, err = NewRequest(context.Background(), http.MethodGet, srv.URL())
+	require.NoError(t, err)
+	start := time.Now()
+	resp, err = pl2.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	require.Equal(t, 2, srv.Requests())
+
+	// a request that can't wait for a token isn't sent
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	req, err = NewRequest(ctx, http.MethodGet, srv.URL())
+	require.NoError(t, err)
+	_, err = pl1.Do(req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, 2, srv.Requests())
+
+	// a pipeline with its own limiter doesn't wait
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err = NewRequest(ctx, http.MethodGet, srv.URL())
+	require.NoError(t, err)
+	resp, err = pl3.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 3, srv.Requests())
+}
@@ -90,6 +90,12 @@ type retryPolicy struct {
 	options policy.RetryOptions
 }
 
+// retryPolicyOpValues is the struct containing the per-try values.
+// It's made available to the policies that follow the retry policy.
+type retryPolicyOpValues struct {
+	try int32
+}
+
 func (p *retryPolicy) Do(req *policy.Request) (resp *http.Response, err error) {
 	options := p.options
 	// check if the retry options have been overridden for this call
@@ -107,9 +113,17 @@ func (p *retryPolicy) Do(req *policy.Request) (resp *http.Response, err error) {
 		defer rwbody.realClose()
 	}
 	try := int32(1)
+	m := getPipelineMetrics(req)
+	start := time.Now()
+	defer func() {
+		attrs := httpMetricAttributes(req, resp, err)
+		recordDuration(req.Raw().Context(), m.opDuration, start, attrs...)
+		m.opTries.Record(req.Raw().Context(), float64(try), attrs...)
+	}()
 	for {
 		resp = nil // reset
 		log.Writef(log.EventRetryPolicy, "=====> Try=%d", try)
+		logRetryRecord(log.LevelDebug, "begin try", try, nil, nil)
 
 		// For each try, seek to the beginning of the Body stream. We do this even for the 1st try because
 		// the stream may not be at offset 0 when we first get it and we want the same behavior for the
@@ -122,6 +136,7 @@ func (p *retryPolicy) Do(req *policy.Request) (resp *http.Response, err error) {
 		if rwbody != nil {
 			req.Raw().Body = rwbody
 		}
+		req.SetOperationValue(retryPolicyOpValues{try: try})
 
 		if options.TryTimeout == 0 {
 			resp, err = req.Next()
@@ -149,11 +164,13 @@ func (p *retryPolicy) Do(req *policy.Request) (resp *http.Response, err error) {
 		if err == nil && !HasStatusCode(resp, options.StatusCodes...) {
 			// if there is no error and the response code isn't in the list of retry codes then we're done.
 			log.Write(log.EventRetryPolicy, "exit due to non-retriable status code")
+			logRetryRecord(log.LevelDebug, "exit due to non-retriable status code", try, resp, err)
 			return
 		} else if ctxErr := req.Raw().Context().Err(); ctxErr != nil {
 			// don't retry if the parent context has been cancelled or its deadline exceeded
 			err = ctxErr
 			log.Writef(log.EventRetryPolicy, "abort due to %v", err)
+			logRetryRecord(log.LevelWarn, "abort", try, resp, err)
 			return
 		}
 
@@ -162,12 +179,14 @@ func (p *retryPolicy) Do(req *policy.Request) (resp *http.Response, err error) {
 		if errors.As(err, &nre) {
 			// the error says it's not retriable so don't retry
 			log.Writef(log.EventRetryPolicy, "non-retriable error %T", nre)
+			logRetryRecord(log.LevelWarn, "non-retriable error", try, resp, err)
 			return
 		}
 
 		if try == options.MaxRetries+1 {
 			// max number of tries has been reached, don't sleep again
 			log.Writef(log.EventRetryPolicy, "MaxRetries %d exceeded", options.MaxRetries)
+			logRetryRecord(log.LevelWarn, "MaxRetries exceeded", try, resp, err, log.Attr{Key: "max_retries", Value: options.MaxRetries})
 			return
 		}
 
@@ -178,24 +197,45 @@ func (p *retryPolicy) Do(req *policy.Request) (resp *http.Response, err error) {
 		} else if delay > options.MaxRetryDelay {
 			// the retry-after delay exceeds the the cap so don't retry
 			log.Writef(log.EventRetryPolicy, "Retry-After delay %s exceeds MaxRetryDelay of %s", delay, options.MaxRetryDelay)
+			logRetryRecord(log.LevelWarn, "Retry-After delay exceeds MaxRetryDelay", try, resp, err,
+				log.Attr{Key: "delay", Value: delay}, log.Attr{Key: "max_retry_delay", Value: options.MaxRetryDelay})
 			return
 		}
 
+		m.retries.Add(req.Raw().Context(), 1, httpMetricAttributes(req, resp, err)...)
+
 		// drain before retrying so nothing is leaked
 		Drain(resp)
 
 		log.Writef(log.EventRetryPolicy, "End Try #%d, Delay=%v", try, delay)
+		logRetryRecord(log.LevelWarn, "retrying", try, resp, err, log.Attr{Key: "delay", Value: delay})
 		select {
 		case <-time.After(delay):
 			try++
 		case <-req.Raw().Context().Done():
 			err = req.Raw().Context().Err()
 			log.Writef(log.EventRetryPolicy, "abort due to %v", err)
+			logRetryRecord(log.LevelWarn, "abort", try, nil, err)
 			return
 		}
 	}
 }
 
+// logRetryRecord writes a record describing the outcome of a try.
+func logRetryRecord(level log.Level, msg string, try int32, resp *http.Response, err error, attrs ...log.Attr) {
+	if !log.ShouldRecord(log.EventRetryPolicy) {
+		return
+	}
+	attrs = append(attrs, log.Attr{Key: "try", Value: try})
+	if err != nil {
+		attrs = append(attrs, log.Attr{Key: "error", Value: err.Error()})
+	}
+	if resp != nil {
+		attrs = append(attrs, log.Attr{Key: "status_code", Value: resp.StatusCode})
+	}
+	log.Record(log.EventRetryPolicy, level, msg, attrs...)
+}
+
 // WithRetryOptions adds the specified RetryOptions to the parent context.
 // Use this to specify custom RetryOptions at the API-call level.
 func WithRetryOptions(parent context.Context, options policy.RetryOptions) context.Context {
@@ -20,6 +20,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/pollers"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/pollers/async"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/pollers/body"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/pollers/fake"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/pollers/loc"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/pollers/op"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
@@ -82,7 +83,10 @@ func NewPoller[T any](resp *http.Response, pl exported.Pipeline, options *NewPol
 	// determine the polling method
 	var opr PollingHandler[T]
 	var err error
-	if async.Applicable(resp) {
+	if fake.Applicable(resp) {
+		// fake poller must be checked first as it's only sent by the azcore/fake transport
+		opr, err = fake.New[T](pl, resp)
+	} else if async.Applicable(resp) {
 		// async poller must be checked first as it can also have a location header
 		opr, err = async.New[T](pl, resp, options.FinalStateVia)
 	} else if op.Applicable(resp) {
@@ -154,6 +158,8 @@ func NewPollerFromResumeToken[T any](token string, pl exported.Pipeline, options
 		opr, _ = loc.New[T](pl, nil)
 	} else if op.CanResume(asJSON) {
 		opr, _ = op.New[T](pl, nil, "")
+	} else if fake.CanResume(asJSON) {
+		opr, _ = fake.New[T](pl, nil)
 	} else if opr != nil {
 		log.Writef(log.EventLRO, "Resuming custom poller %T.", opr)
 	} else {
@@ -190,11 +196,24 @@ type Poller[T any] struct {
 	done   bool
 }
 
+// PollProgress is the progress of a long-running operation. It's reported for operations that use
+// the Azure-AsyncOperation or Operation-Location patterns. A custom PollingHandler[T] can report
+// progress by implementing method Progress() PollProgress.
+type PollProgress = pollers.Progress
+
 // PollUntilDoneOptions contains the optional values for the Poller[T].PollUntilDone() method.
 type PollUntilDoneOptions struct {
 	// Frequency is the time to wait between polling intervals in absence of a Retry-After header. Allowed minimum is one second.
-	// Pass zero to accept the default value (30s).
+	// Pass zero to accept the default value (30s). Frequency is ignored when Backoff is set.
 	Frequency time.Duration
+
+	// Backoff determines the time to wait between polling intervals in absence of a Retry-After header.
+	// Unlike Frequency, its delays can be less than one second.
+	Backoff PollingBackoff
+
+	// Progress is called after each polling attempt that doesn't reach a terminal state,
+	// when the operation reports its progress. See PollProgress for more information.
+	Progress func(PollProgress)
 }
 
 // PollUntilDone will poll the service endpoint until a terminal state is reached, an error is received, or the context expires.
@@ -212,19 +231,31 @@ func (p *Poller[T]) PollUntilDone(ctx context.Context, options *PollUntilDoneOpt
 	}
 
 	// skip the floor check when executing tests so they don't take so long
-	if isTest := flag.Lookup("test.v"); isTest == nil && cp.Frequency < time.Second {
+	if isTest := flag.Lookup("test.v"); isTest == nil && cp.Backoff == nil && cp.Frequency < time.Second {
 		return *new(T), errors.New("polling frequency minimum is one second")
 	}
 
 	start := time.Now()
 	logPollUntilDoneExit := func(v interface{}) {
 		log.Writef(log.EventLRO, "END PollUntilDone() for %T: %v, total time: %s", p.op, v, time.Since(start))
+		if log.ShouldRecord(log.EventLRO) {
+			attrs := []log.Attr{{Key: "poller", Value: fmt.Sprintf("%T", p.op)}, {Key: "duration", Value: time.Since(start)}}
+			if err, ok := v.(error); ok {
+				log.Record(log.EventLRO, log.LevelError, "end PollUntilDone", append(attrs, log.Attr{Key: "error", Value: err.Error()})...)
+			} else {
+				log.Record(log.EventLRO, log.LevelInfo, "end PollUntilDone", append(attrs, log.Attr{Key: "result", Value: v})...)
+			}
+		}
 	}
 	log.Writef(log.EventLRO, "BEGIN PollUntilDone() for %T", p.op)
+	if log.ShouldRecord(log.EventLRO) {
+		log.Record(log.EventLRO, log.LevelDebug, "begin PollUntilDone", log.Attr{Key: "poller", Value: fmt.Sprintf("%T", p.op)})
+	}
 	if p.resp != nil {
 		// initial check for a retry-after header existing on the initial response
 		if retryAfter := shared.RetryAfter(p.resp); retryAfter > 0 {
 			log.Writef(log.EventLRO, "initial Retry-After delay for %s", retryAfter.String())
+			logPollDelayRecord(retryAfter, true)
 			if err := shared.Delay(ctx, retryAfter); err != nil {
 				logPollUntilDoneExit(err)
 				return *new(T), err
@@ -232,7 +263,7 @@ func (p *Poller[T]) PollUntilDone(ctx context.Context, options *PollUntilDoneOpt
 		}
 	}
 	// begin polling the endpoint until a terminal state is reached
-	for {
+	for attempt := 1; ; attempt++ {
 		resp, err := p.Poll(ctx)
 		if err != nil {
 			logPollUntilDoneExit(err)
@@ -242,12 +273,20 @@ func (p *Poller[T]) PollUntilDone(ctx context.Context, options *PollUntilDoneOpt
 			logPollUntilDoneExit("succeeded")
 			return p.Result(ctx)
 		}
+		if reporter, ok := p.op.(interface{ Progress() PollProgress }); ok && cp.Progress != nil {
+			cp.Progress(reporter.Progress())
+		}
 		d := cp.Frequency
+		if cp.Backoff != nil {
+			d = cp.Backoff.Delay(attempt)
+		}
 		if retryAfter := shared.RetryAfter(resp); retryAfter > 0 {
 			log.Writef(log.EventLRO, "Retry-After delay for %s", retryAfter.String())
 			d = retryAfter
+			logPollDelayRecord(d, true)
 		} else {
 			log.Writef(log.EventLRO, "delay for %s", d.String())
+			logPollDelayRecord(d, false)
 		}
 		if err = shared.Delay(ctx, d); err != nil {
 			logPollUntilDoneExit(err)
@@ -324,3 +363,10 @@ func (p *Poller[T]) ResumeToken() (string, error) {
 	}
 	return tk, err
 }
+
+// logPollDelayRecord writes a record of the delay before polling again.
+func logPollDelayRecord(d time.Duration, retryAfter bool) {
+	if log.ShouldRecord(log.EventLRO) {
+		log.Record(log.EventLRO, log.LevelDebug, "delay", log.Attr{Key: "delay", Value: d}, log.Attr{Key: "retry_after", Value: retryAfter})
+	}
+}
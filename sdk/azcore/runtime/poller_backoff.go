@@ -0,0 +1,73 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// PollingBackoff determines the delay between polling intervals of Poller[T].PollUntilDone.
+// A Retry-After header in a polling response takes precedence over the backoff's delay.
+type PollingBackoff interface {
+	// Delay returns how long to wait after the specified polling attempt.
+	// The first attempt is one.
+	Delay(attempt int) time.Duration
+}
+
+// PollingBackoffFunc is a func that implements the PollingBackoff interface.
+// Use it to compute the delay of each attempt with custom logic.
+type PollingBackoffFunc func(attempt int) time.Duration
+
+// Delay implements the PollingBackoff interface for PollingBackoffFunc.
+func (f PollingBackoffFunc) Delay(attempt int) time.Duration {
+	return f(attempt)
+}
+
+// ExponentialPollingBackoff is a PollingBackoff whose delay grows exponentially up to a maximum.
+// Zero-value fields will have their specified default values applied during use.
+type ExponentialPollingBackoff struct {
+	// InitialDelay is the delay after the first polling attempt.
+	// The default value is one second.
+	InitialDelay time.Duration
+
+	// MaxDelay is the maximum delay between polling attempts.
+	// The default value is one minute.
+	MaxDelay time.Duration
+
+	// Multiplier is the factor by which the delay grows after each attempt.
+	// The default value is two.
+	Multiplier float64
+
+	// Jitter randomizes each delay by up to the specified fraction of it, e.g. 0.2 randomizes
+	// delays by +/-20%. Randomized delays don't exceed MaxDelay. Valid values are in [0, 1].
+	// The default value of zero disables jitter.
+	Jitter float64
+}
+
+// Delay implements the PollingBackoff interface for ExponentialPollingBackoff.
+func (b ExponentialPollingBackoff) Delay(attempt int) time.Duration {
+	if b.InitialDelay <= 0 {
+		b.InitialDelay = time.Second
+	}
+	if b.MaxDelay <= 0 {
+		b.MaxDelay = time.Minute
+	}
+	if b.Multiplier <= 0 {
+		b.Multiplier = 2
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := math.Min(float64(b.InitialDelay)*math.Pow(b.Multiplier, float64(attempt-1)), float64(b.MaxDelay))
+	if b.Jitter > 0 {
+		// NOTE: We want math/rand; not crypto/rand
+		delay *= 1 + math.Min(b.Jitter, 1)*(2*rand.Float64()-1)
+	}
+	return time.Duration(math.Min(delay, float64(b.MaxDelay)))
+}
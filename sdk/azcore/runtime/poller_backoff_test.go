@@ -0,0 +1,48 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExponentialPollingBackoff(t *testing.T) {
+	b := ExponentialPollingBackoff{}
+	require.Equal(t, time.Second, b.Delay(0))
+	require.Equal(t, time.Second, b.Delay(1))
+	require.Equal(t, 2*time.Second, b.Delay(2))
+	require.Equal(t, 32*time.Second, b.Delay(6))
+	require.Equal(t, time.Minute, b.Delay(7))
+	require.Equal(t, time.Minute, b.Delay(1000))
+
+	b = ExponentialPollingBackoff{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second, Multiplier: 1.5}
+	require.Equal(t, 100*time.Millisecond, b.Delay(1))
+	require.Equal(t, 150*time.Millisecond, b.Delay(2))
+	require.Equal(t, 225*time.Millisecond, b.Delay(3))
+	require.Equal(t, time.Second, b.Delay(10))
+}
+
+func TestExponentialPollingBackoffJitter(t *testing.T) {
+	b := ExponentialPollingBackoff{InitialDelay: time.Second, MaxDelay: 4 * time.Second, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		d := b.Delay(2)
+		require.GreaterOrEqual(t, d, time.Second)
+		require.LessOrEqual(t, d, 3*time.Second)
+		// jitter doesn't exceed the maximum
+		require.LessOrEqual(t, b.Delay(3), 4*time.Second)
+	}
+}
+
+func TestPollingBackoffFunc(t *testing.T) {
+	var b PollingBackoff = PollingBackoffFunc(func(attempt int) time.Duration {
+		return time.Duration(attempt) * time.Millisecond
+	})
+	require.Equal(t, 3*time.Millisecond, b.Delay(3))
+}
@@ -0,0 +1,241 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/log"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+)
+
+const defaultPollerGroupConcurrency = 8
+
+// PollerGroupOptions contains the optional values for NewPollerGroup.
+type PollerGroupOptions struct {
+	// Frequency is the time to wait between polling intervals of an operation in absence of a Retry-After header.
+	// Pass zero to accept the default value (30s).
+	Frequency time.Duration
+
+	// MaxConcurrency is the maximum number of polling requests in flight at once.
+	// Pass zero to accept the default value (8).
+	MaxConcurrency int
+}
+
+// PollerGroupResult is the outcome of an operation in a PollerGroup.
+type PollerGroupResult struct {
+	// ID is the ID passed to AddPoller for the operation.
+	ID string
+
+	// Result is the result of the operation, a value of type T for a Poller[T].
+	// It's nil when Err isn't nil.
+	Result interface{}
+
+	// Err is the error returned when polling the operation or retrieving its result.
+	Err error
+}
+
+// PollerGroup waits on many long-running operations with a shared scheduler. Unlike calling
+// Poller[T].PollUntilDone for each operation, it limits the number of polling requests in flight.
+// Add pollers with AddPoller, then call Wait.
+type PollerGroup struct {
+	options PollerGroupOptions
+
+	mu      sync.Mutex
+	entries []*pollerGroupEntry
+	ids     map[string]struct{}
+	waiting bool
+}
+
+// NewPollerGroup creates a PollerGroup.
+// options: pass nil to accept the default values.
+func NewPollerGroup(options *PollerGroupOptions) *PollerGroup {
+	g := &PollerGroup{ids: map[string]struct{}{}}
+	if options != nil {
+		g.options = *options
+	}
+	if g.options.Frequency <= 0 {
+		g.options.Frequency = 30 * time.Second
+	}
+	if g.options.MaxConcurrency <= 0 {
+		g.options.MaxConcurrency = defaultPollerGroupConcurrency
+	}
+	return g
+}
+
+// AddPoller adds poller to the group. Its outcome will be reported with the specified ID, which must be unique
+// within the group. Pollers must be added before calling Wait.
+func AddPoller[T any](g *PollerGroup, id string, poller *Poller[T]) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.waiting {
+		return errors.New("can't add a poller to a group that's being waited on")
+	}
+	if _, ok := g.ids[id]; ok {
+		return fmt.Errorf("the group already contains a poller with ID %s", id)
+	}
+	g.ids[id] = struct{}{}
+	e := &pollerGroupEntry{
+		id:   id,
+		next: time.Now(),
+		poll: poller.Poll,
+		done: poller.Done,
+		result: func(ctx context.Context) (interface{}, error) {
+			return poller.Result(ctx)
+		},
+	}
+	// respect a Retry-After on the initial response
+	if retryAfter := shared.RetryAfter(poller.resp); retryAfter > 0 && !poller.Done() {
+		e.next = e.next.Add(retryAfter)
+	}
+	g.entries = append(g.entries, e)
+	return nil
+}
+
+// Wait polls the group's operations until they complete or ctx is cancelled. It returns a channel that receives
+// the outcome of each operation as it completes and is closed after every operation's outcome has been sent. When
+// ctx is cancelled, the outcome of the incomplete operations is ctx's error. The channel has capacity for every
+// outcome so the group doesn't wait on a slow receiver. Wait can be called once.
+func (g *PollerGroup) Wait(ctx context.Context) <-chan PollerGroupResult {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	results := make(chan PollerGroupResult, len(g.entries))
+	if g.waiting {
+		close(results)
+		return results
+	}
+	g.waiting = true
+	s := &pollerGroupScheduler{
+		options:     g.options,
+		results:     results,
+		completions: make(chan *pollerGroupEntry),
+	}
+	for _, e := range g.entries {
+		heap.Push(&s.queue, e)
+	}
+	go s.run(ctx)
+	return results
+}
+
+// pollerGroupEntry is an operation in a PollerGroup.
+type pollerGroupEntry struct {
+	id     string
+	poll   func(context.Context) (*http.Response, error)
+	done   func() bool
+	result func(context.Context) (interface{}, error)
+
+	// next is when to poll the operation next
+	next time.Time
+
+	// outcome is set when the operation completes
+	outcome *PollerGroupResult
+}
+
+// pollerGroupQueue is a min-heap of entries ordered by the time of their next poll.
+type pollerGroupQueue []*pollerGroupEntry
+
+func (q pollerGroupQueue) Len() int            { return len(q) }
+func (q pollerGroupQueue) Less(i, j int) bool  { return q[i].next.Before(q[j].next) }
+func (q pollerGroupQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *pollerGroupQueue) Push(x interface{}) { *q = append(*q, x.(*pollerGroupEntry)) }
+func (q *pollerGroupQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+// pollerGroupScheduler polls the operations of a PollerGroup.
+type pollerGroupScheduler struct {
+	options     PollerGroupOptions
+	queue       pollerGroupQueue
+	inFlight    int
+	results     chan PollerGroupResult
+	completions chan *pollerGroupEntry
+}
+
+func (s *pollerGroupScheduler) run(ctx context.Context) {
+	defer close(s.results)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		// start polling the operations that are due, up to the concurrency limit
+		now := time.Now()
+		for len(s.queue) > 0 && s.inFlight < s.options.MaxConcurrency && !s.queue[0].next.After(now) {
+			s.inFlight++
+			go s.poll(ctx, heap.Pop(&s.queue).(*pollerGroupEntry))
+		}
+		if len(s.queue) == 0 && s.inFlight == 0 {
+			return
+		}
+		var timerC <-chan time.Time
+		if len(s.queue) > 0 && s.inFlight < s.options.MaxConcurrency {
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(time.Until(s.queue[0].next))
+			timerC = timer.C
+		}
+		select {
+		case e := <-s.completions:
+			s.complete(e)
+		case <-timerC:
+		case <-ctx.Done():
+			// in flight polls fail with ctx's error
+			for s.inFlight > 0 {
+				s.complete(<-s.completions)
+			}
+			for _, e := range s.queue {
+				s.results <- PollerGroupResult{ID: e.id, Err: ctx.Err()}
+			}
+			return
+		}
+	}
+}
+
+// complete handles an entry returned by poll. Must be called from run.
+func (s *pollerGroupScheduler) complete(e *pollerGroupEntry) {
+	s.inFlight--
+	if e.outcome != nil {
+		log.Writef(log.EventLRO, "Poller group operation %s completed: %v", e.id, e.outcome.Err)
+		s.results <- *e.outcome
+		return
+	}
+	heap.Push(&s.queue, e)
+}
+
+// poll polls the operation once, setting either its outcome or the time of its next poll.
+func (s *pollerGroupScheduler) poll(ctx context.Context, e *pollerGroupEntry) {
+	defer func() { s.completions <- e }()
+	resp, err := e.poll(ctx)
+	if err != nil {
+		e.outcome = &PollerGroupResult{ID: e.id, Err: err}
+		return
+	}
+	if e.done() {
+		result, err := e.result(ctx)
+		if err != nil {
+			result = nil
+		}
+		e.outcome = &PollerGroupResult{ID: e.id, Result: result, Err: err}
+		return
+	}
+	d := s.options.Frequency
+	if retryAfter := shared.RetryAfter(resp); retryAfter > 0 {
+		d = retryAfter
+	}
+	e.next = time.Now().Add(d)
+}
@@ -0,0 +1,169 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/log"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/require"
+)
+
+// newPollerGroupTransport returns a transport for operations /res/{n} whose status monitor /op/{n}
+// reports InProgress pollCount times before reporting Succeeded.
+func newPollerGroupTransport(pollCount int, delay time.Duration, inFlight, maxInFlight *int32) policy.Transporter {
+	var mu sync.Mutex
+	polls := map[string]int{}
+	return shared.TransportFunc(func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(inFlight, 1)
+		defer atomic.AddInt32(inFlight, -1)
+		for {
+			max := atomic.LoadInt32(maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(delay)
+		body := successResp
+		if strings.HasPrefix(req.URL.Path, "/op/") {
+			mu.Lock()
+			polls[req.URL.Path]++
+			count := polls[req.URL.Path]
+			mu.Unlock()
+			body = statusInProgress
+			if count > pollCount {
+				body = statusSucceeded
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+}
+
+func newPollerGroupPoller(t *testing.T, pl Pipeline, n int) *Poller[mockType] {
+	resp, _ := initialResponse(http.MethodPut, fmt.Sprintf("https://contoso.com/res/%d", n), strings.NewReader(provStateStarted))
+	resp.Header.Set(shared.HeaderAzureAsync, fmt.Sprintf("https://contoso.com/op/%d", n))
+	resp.StatusCode = http.StatusCreated
+	poller, err := NewPoller[mockType](resp, pl, nil)
+	require.NoError(t, err)
+	return poller
+}
+
+func TestPollerGroup(t *testing.T) {
+	// pollers run concurrently; listeners set by other tests aren't safe for that
+	log.SetListener(nil)
+	defer log.SetListener(nil)
+	var inFlight, maxInFlight int32
+	pl := NewPipeline("test", "v0.1.0", PipelineOptions{}, &policy.ClientOptions{
+		Transport: newPollerGroupTransport(2, 10*time.Millisecond, &inFlight, &maxInFlight),
+	})
+	g := NewPollerGroup(&PollerGroupOptions{Frequency: time.Millisecond, MaxConcurrency: 2})
+	const count = 5
+	for i := 0; i < count; i++ {
+		require.NoError(t, AddPoller(g, fmt.Sprint(i), newPollerGroupPoller(t, pl, i)))
+	}
+	require.Error(t, AddPoller(g, "0", newPollerGroupPoller(t, pl, 0)))
+
+	ids := map[string]bool{}
+	for result := range g.Wait(context.Background()) {
+		require.NoError(t, result.Err)
+		require.Equal(t, "value", *result.Result.(mockType).Field)
+		ids[result.ID] = true
+	}
+	require.Len(t, ids, count)
+	require.EqualValues(t, 2, maxInFlight)
+
+	require.Error(t, AddPoller(g, "late", newPollerGroupPoller(t, pl, count)))
+	_, ok := <-g.Wait(context.Background())
+	require.False(t, ok)
+}
+
+func TestPollerGroupRetryAfter(t *testing.T) {
+	log.SetListener(nil)
+	defer log.SetListener(nil)
+	var polled time.Time
+	pl := NewPipeline("test", "v0.1.0", PipelineOptions{}, &policy.ClientOptions{
+		Retry: policy.RetryOptions{MaxRetries: -1},
+		Transport: shared.TransportFunc(func(req *http.Request) (*http.Response, error) {
+			resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Request: req}
+			if polled.IsZero() {
+				polled = time.Now()
+				resp.Header.Set(shared.HeaderRetryAfter, "1")
+				resp.Body = io.NopCloser(strings.NewReader(statusInProgress))
+			} else if req.URL.Path == "/op/0" {
+				if time.Since(polled) < time.Second {
+					return nil, errors.New("polled before Retry-After elapsed")
+				}
+				resp.Body = io.NopCloser(strings.NewReader(statusSucceeded))
+			} else {
+				resp.Body = io.NopCloser(strings.NewReader(successResp))
+			}
+			return resp, nil
+		}),
+	})
+	g := NewPollerGroup(&PollerGroupOptions{Frequency: time.Millisecond})
+	require.NoError(t, AddPoller(g, "op", newPollerGroupPoller(t, pl, 0)))
+	result, ok := <-g.Wait(context.Background())
+	require.True(t, ok)
+	require.NoError(t, result.Err)
+	require.Equal(t, "op", result.ID)
+	_, ok = <-g.Wait(context.Background())
+	require.False(t, ok)
+}
+
+func TestPollerGroupError(t *testing.T) {
+	log.SetListener(nil)
+	defer log.SetListener(nil)
+	pl := NewPipeline("test", "v0.1.0", PipelineOptions{}, &policy.ClientOptions{
+		Retry: policy.RetryOptions{MaxRetries: -1},
+		Transport: shared.TransportFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("failed")
+		}),
+	})
+	g := NewPollerGroup(nil)
+	require.NoError(t, AddPoller(g, "op", newPollerGroupPoller(t, pl, 0)))
+	result := <-g.Wait(context.Background())
+	require.Equal(t, "op", result.ID)
+	require.Error(t, result.Err)
+	require.Nil(t, result.Result)
+}
+
+func TestPollerGroupContextCanceled(t *testing.T) {
+	log.SetListener(nil)
+	defer log.SetListener(nil)
+	var inFlight, maxInFlight int32
+	pl := NewPipeline("test", "v0.1.0", PipelineOptions{}, &policy.ClientOptions{
+		Transport: newPollerGroupTransport(1000, 0, &inFlight, &maxInFlight),
+	})
+	g := NewPollerGroup(&PollerGroupOptions{Frequency: time.Hour})
+	for i := 0; i < 3; i++ {
+		require.NoError(t, AddPoller(g, fmt.Sprint(i), newPollerGroupPoller(t, pl, i)))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	results := 0
+	for result := range g.Wait(ctx) {
+		require.ErrorIs(t, result.Err, context.DeadlineExceeded)
+		results++
+	}
+	require.Equal(t, 3, results)
+}
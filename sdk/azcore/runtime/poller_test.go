@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/pollers/async"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/pollers/body"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/internal/shared"
+	azlog "github.com/Azure/azure-sdk-for-go/sdk/azcore/log"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/mock"
 	"github.com/stretchr/testify/require"
@@ -832,6 +833,74 @@ func TestNewPollerAsync(t *testing.T) {
 	}
 }
 
+func TestPollUntilDoneRecords(t *testing.T) {
+	var records []azlog.Record
+	azlog.SetRecordListener(func(r azlog.Record) {
+		if r.Event == azlog.EventLRO {
+			records = append(records, r)
+		}
+	})
+	defer azlog.SetRecordListener(nil)
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithBody([]byte(statusInProgress)))
+	srv.AppendResponse(mock.WithBody([]byte(statusSucceeded)))
+	srv.AppendResponse(mock.WithBody([]byte(successResp)))
+	resp, _ := initialResponse(http.MethodPut, srv.URL(), strings.NewReader(provStateStarted))
+	resp.Header.Set(shared.HeaderAzureAsync, srv.URL())
+	resp.StatusCode = http.StatusCreated
+	poller, err := NewPoller[mockType](resp, getPipeline(srv), nil)
+	require.NoError(t, err)
+	_, err = poller.PollUntilDone(context.Background(), &PollUntilDoneOptions{Frequency: time.Millisecond})
+	require.NoError(t, err)
+
+	var messages []string
+	var states []interface{}
+	for _, r := range records {
+		messages = append(messages, r.Message)
+		if r.Message == "state" {
+			states = append(states, r.Attrs[0].Value)
+		}
+	}
+	require.Equal(t, []string{"begin PollUntilDone", "state", "delay", "state", "end PollUntilDone"}, messages)
+	require.Equal(t, []interface{}{"InProgress", "Succeeded"}, states)
+	end := records[len(records)-1]
+	require.Equal(t, azlog.LevelInfo, end.Level)
+}
+
+func TestPollUntilDoneBackoffAndProgress(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithBody([]byte(`{ "status": "InProgress", "percentComplete": 25 }`)))
+	srv.AppendResponse(mock.WithBody([]byte(`{ "status": "InProgress", "percentComplete": 75.5 }`)))
+	srv.AppendResponse(mock.WithBody([]byte(statusSucceeded)))
+	srv.AppendResponse(mock.WithBody([]byte(successResp)))
+	resp, _ := initialResponse(http.MethodPut, srv.URL(), strings.NewReader(provStateStarted))
+	resp.Header.Set(shared.HeaderAzureAsync, srv.URL())
+	resp.StatusCode = http.StatusCreated
+	poller, err := NewPoller[mockType](resp, getPipeline(srv), nil)
+	require.NoError(t, err)
+
+	var attempts []int
+	var progress []PollProgress
+	result, err := poller.PollUntilDone(context.Background(), &PollUntilDoneOptions{
+		Backoff: PollingBackoffFunc(func(attempt int) time.Duration {
+			attempts = append(attempts, attempt)
+			return time.Millisecond
+		}),
+		Progress: func(p PollProgress) {
+			progress = append(progress, p)
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "value", *result.Field)
+	require.Equal(t, []int{1, 2}, attempts)
+	require.Len(t, progress, 2)
+	require.Equal(t, "InProgress", progress[0].Status)
+	require.EqualValues(t, 25, *progress[0].PercentComplete)
+	require.EqualValues(t, 75.5, *progress[1].PercentComplete)
+}
+
 func TestNewPollerBody(t *testing.T) {
 	srv, close := mock.NewServer()
 	defer close()
@@ -9,6 +9,7 @@ package tracing
 
 import (
 	"context"
+	"net/http"
 )
 
 // ProviderOptions contains the optional values when creating a Provider.
@@ -45,21 +46,41 @@ func (p Provider) NewTracer(name, version string) (tracer Tracer) {
 
 // TracerOptions contains the optional values when creating a Tracer.
 type TracerOptions struct {
-	// for future expansion
+	// Inject contains the implementation for the Tracer.Inject method.
+	// It writes the span context contained in ctx to the HTTP header, typically as a W3C traceparent header.
+	Inject func(ctx context.Context, header http.Header)
 }
 
 // NewTracer creates a Tracer with the specified values.
 //   - newSpanFn is the underlying implementation for creating Span instances
 //   - options contains optional values; pass nil to accept the default value
 func NewTracer(newSpanFn func(ctx context.Context, spanName string, options *SpanOptions) (context.Context, Span), options *TracerOptions) Tracer {
+	if options == nil {
+		options = &TracerOptions{}
+	}
 	return Tracer{
 		newSpanFn: newSpanFn,
+		injectFn:  options.Inject,
 	}
 }
 
 // Tracer is the factory that creates Span instances.
 type Tracer struct {
 	newSpanFn func(ctx context.Context, spanName string, options *SpanOptions) (context.Context, Span)
+	injectFn  func(ctx context.Context, header http.Header)
+}
+
+// Enabled returns true if this Tracer is capable of creating Spans.
+func (t Tracer) Enabled() bool {
+	return t.newSpanFn != nil
+}
+
+// Inject propagates the span context contained in ctx to the specified HTTP header.
+// This is a no-op if the Tracer wasn't created with an Inject implementation.
+func (t Tracer) Inject(ctx context.Context, header http.Header) {
+	if t.injectFn != nil {
+		t.injectFn(ctx, header)
+	}
 }
 
 // Start creates a new span and a context.Context that contains it.
@@ -8,6 +8,7 @@ package tracing
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -17,6 +18,8 @@ func TestProviderZeroValues(t *testing.T) {
 	pr := Provider{}
 	tr := pr.NewTracer("name", "version")
 	require.Zero(t, tr)
+	require.False(t, tr.Enabled())
+	tr.Inject(context.Background(), http.Header{})
 	ctx, sp := tr.Start(context.Background(), "spanName", nil)
 	require.Equal(t, context.Background(), ctx)
 	require.Zero(t, sp)
@@ -43,10 +46,19 @@ func TestProvider(t *testing.T) {
 				SetAttributes: func(...Attribute) { setAttributesCalled = true },
 				SetStatus:     func(SpanStatus, string) { setStatusCalled = true },
 			})
-		}, nil)
+		}, &TracerOptions{
+			Inject: func(ctx context.Context, header http.Header) {
+				header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+			},
+		})
 	}, nil)
 	tr := pr.NewTracer("name", "version")
 	require.NotZero(t, tr)
+	require.True(t, tr.Enabled())
+
+	header := http.Header{}
+	tr.Inject(context.Background(), header)
+	require.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", header.Get("traceparent"))
 
 	ctx, sp := tr.Start(context.Background(), "name", nil)
 	require.NotEqual(t, context.Background(), ctx)
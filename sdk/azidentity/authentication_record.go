@@ -0,0 +1,91 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azidentity
+
+import (
+	"reflect"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+)
+
+const authenticationRecordVersion = "1.0"
+
+// armAudiences are the Azure Resource Manager audiences of the well-known clouds, by authority host. The cloud
+// package registers these only when a program imports the arm packages.
+var armAudiences = map[string]string{
+	cloud.AzureChina.ActiveDirectoryAuthorityHost:      "https://management.core.chinacloudapi.cn",
+	cloud.AzureGovernment.ActiveDirectoryAuthorityHost: "https://management.core.usgovcloudapi.net",
+	cloud.AzurePublic.ActiveDirectoryAuthorityHost:     "https://management.core.windows.net/",
+}
+
+// AuthenticationRecord is non-secret account information about an authenticated user. Interactive credentials such
+// as DeviceCodeCredential and InteractiveBrowserCredential use it to find the user's tokens in a persistent Cache,
+// so they can authenticate that user silently in another process. Get a record by calling one of these credentials'
+// Authenticate method. Records can be serialized to and from JSON.
+type AuthenticationRecord struct {
+	// Authority is the host of the authority that authenticated the user, for example "login.microsoftonline.com".
+	Authority string `json:"authority"`
+
+	// ClientID is the ID of the application the user authenticated to.
+	ClientID string `json:"clientId"`
+
+	// HomeAccountID uniquely identifies the account.
+	HomeAccountID string `json:"homeAccountId"`
+
+	// TenantID is the tenant the user authenticated in.
+	TenantID string `json:"tenantId"`
+
+	// Username is the user's name, such as an email address.
+	Username string `json:"username"`
+
+	// Version is the version of the record's format.
+	Version string `json:"version"`
+}
+
+func newAuthenticationRecord(a public.Account, clientID string) AuthenticationRecord {
+	return AuthenticationRecord{
+		Authority:     a.Environment,
+		ClientID:      clientID,
+		HomeAccountID: a.HomeAccountID,
+		TenantID:      a.Realm,
+		Username:      a.PreferredUsername,
+		Version:       authenticationRecordVersion,
+	}
+}
+
+// account returns the MSAL account identified by the record
+func (r AuthenticationRecord) account() public.Account {
+	return public.Account{
+		Environment:       r.Authority,
+		HomeAccountID:     r.HomeAccountID,
+		PreferredUsername: r.Username,
+		Realm:             r.TenantID,
+	}
+}
+
+// authenticateScopes returns the scopes Authenticate requests when the caller doesn't specify any:
+// those of Azure Resource Manager in the configured cloud, which any user can get a token for.
+func authenticateScopes(opts *policy.TokenRequestOptions, co azcore.ClientOptions) policy.TokenRequestOptions {
+	if opts != nil && len(opts.Scopes) > 0 {
+		return *opts
+	}
+	c := cloud.AzurePublic
+	if !reflect.ValueOf(co.Cloud).IsZero() {
+		c = co.Cloud
+	}
+	audience := armAudiences[c.ActiveDirectoryAuthorityHost]
+	if conf, ok := c.Services[cloud.ResourceManager]; ok && conf.Audience != "" {
+		audience = conf.Audience
+	}
+	if audience == "" {
+		audience = armAudiences[cloud.AzurePublic.ActiveDirectoryAuthorityHost]
+	}
+	return policy.TokenRequestOptions{Scopes: []string{audience + defaultSuffix}}
+}
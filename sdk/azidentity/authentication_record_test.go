@@ -0,0 +1,160 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azidentity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+)
+
+// scopeRecordingClient records the scopes of interactive token requests
+type scopeRecordingClient struct {
+	fakePublicClient
+	scopes *[]string
+}
+
+func (s scopeRecordingClient) AcquireTokenInteractive(ctx context.Context, scopes []string, options ...public.AcquireInteractiveOption) (public.AuthResult, error) {
+	*s.scopes = scopes
+	return s.fakePublicClient.AcquireTokenInteractive(ctx, scopes, options...)
+}
+
+func TestInteractiveBrowserCredential_Authenticate(t *testing.T) {
+	account := public.Account{
+		Environment:       "login.microsoftonline.com",
+		HomeAccountID:     "object.tenant",
+		PreferredUsername: fakeUsername,
+		Realm:             fakeTenantID,
+	}
+	for _, test := range []struct {
+		desc     string
+		opts     *policy.TokenRequestOptions
+		cloud    cloud.Configuration
+		expected string
+	}{
+		{desc: "default scope", expected: "https://management.core.windows.net//.default"},
+		{desc: "cloud", cloud: cloud.AzureChina, expected: "https://management.core.chinacloudapi.cn/.default"},
+		{desc: "specified scope", opts: &policy.TokenRequestOptions{Scopes: []string{liveTestScope}}, expected: liveTestScope},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			cred, err := NewInteractiveBrowserCredential(&InteractiveBrowserCredentialOptions{
+				ClientID:      fakeClientID,
+				ClientOptions: azcore.ClientOptions{Cloud: test.cloud},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var scopes []string
+			cred.client = scopeRecordingClient{fakePublicClient{ar: public.AuthResult{Account: account, AccessToken: tokenValue}}, &scopes}
+			record, err := cred.Authenticate(context.Background(), test.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(scopes) != 1 || scopes[0] != test.expected {
+				t.Fatalf("expected scope %q, got %v", test.expected, scopes)
+			}
+			expected := AuthenticationRecord{
+				Authority:     account.Environment,
+				ClientID:      fakeClientID,
+				HomeAccountID: account.HomeAccountID,
+				TenantID:      fakeTenantID,
+				Username:      fakeUsername,
+				Version:       authenticationRecordVersion,
+			}
+			if record != expected {
+				t.Fatalf("expected %+v, got %+v", expected, record)
+			}
+		})
+	}
+}
+
+func TestAuthenticationRecord(t *testing.T) {
+	record := AuthenticationRecord{
+		Authority:     "login.microsoftonline.com",
+		ClientID:      fakeClientID,
+		HomeAccountID: "object.tenant",
+		TenantID:      fakeTenantID,
+		Username:      fakeUsername,
+		Version:       authenticationRecordVersion,
+	}
+	b, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unmarshaled AuthenticationRecord
+	if err = json.Unmarshal(b, &unmarshaled); err != nil {
+		t.Fatal(err)
+	}
+	if unmarshaled != record {
+		t.Fatalf("expected %+v, got %+v", record, unmarshaled)
+	}
+
+	// credentials constructed with a record should use its account, client and tenant
+	dc, err := NewDeviceCodeCredential(&DeviceCodeCredentialOptions{AuthenticationRecord: record})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ib, err := NewInteractiveBrowserCredential(&InteractiveBrowserCredentialOptions{AuthenticationRecord: record})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []public.Account{dc.account, ib.account} {
+		if !reflect.DeepEqual(a, record.account()) {
+			t.Fatalf("expected account %+v, got %+v", record.account(), a)
+		}
+	}
+	for _, o := range []struct{ clientID, tenantID string }{{dc.options.ClientID, dc.options.TenantID}, {ib.options.ClientID, ib.options.TenantID}} {
+		if o.clientID != fakeClientID || o.tenantID != fakeTenantID {
+			t.Fatalf("unexpected client %q and tenant %q", o.clientID, o.tenantID)
+		}
+	}
+}
+
+func TestDisableAutomaticAuthentication(t *testing.T) {
+	dc, err := NewDeviceCodeCredential(&DeviceCodeCredentialOptions{
+		DisableAutomaticAuthentication: true,
+		UserPrompt: func(context.Context, DeviceCodeMessage) error {
+			t.Fatal("the credential shouldn't prompt the user")
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ib, err := NewInteractiveBrowserCredential(&InteractiveBrowserCredentialOptions{DisableAutomaticAuthentication: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc.client = fakePublicClient{}
+	ib.client = fakePublicClient{err: errors.New("the credential shouldn't authenticate interactively")}
+	for _, cred := range []azcore.TokenCredential{dc, ib} {
+		_, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{liveTestScope}})
+		if !errors.Is(err, ErrAuthenticationRequired) {
+			t.Fatalf("expected ErrAuthenticationRequired, got %v", err)
+		}
+	}
+
+	// silent authentication should still succeed, and Authenticate should authenticate interactively
+	ib.client = fakePublicClient{silentAuth: true, ar: public.AuthResult{AccessToken: tokenValue}}
+	tk, err := ib.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{liveTestScope}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tk.Token != tokenValue {
+		t.Fatalf("unexpected token %q", tk.Token)
+	}
+	if _, err = ib.Authenticate(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+}
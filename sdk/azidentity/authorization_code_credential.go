@@ -0,0 +1,139 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azidentity
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/confidential"
+)
+
+const credNameAuthCode = "AuthorizationCodeCredential"
+
+// AuthorizationCodeCredential authenticates a user with an authorization code a web application received from
+// Azure Active Directory at its redirect URL. The credential redeems the code the first time GetToken is called,
+// then gets tokens silently with the refresh token Azure Active Directory returned. See
+// [Azure Active Directory documentation] for more details.
+//
+// [Azure Active Directory documentation]: https://learn.microsoft.com/azure/active-directory/develop/v2-oauth2-auth-code-flow
+type AuthorizationCodeCredential struct {
+	account      confidential.Account
+	authCode     string
+	client       confidentialClient
+	codeVerifier string
+	mtx          *sync.Mutex
+	redirectURL  string
+}
+
+// AuthorizationCodeCredentialOptions contains optional parameters for AuthorizationCodeCredential.
+type AuthorizationCodeCredentialOptions struct {
+	azcore.ClientOptions
+
+	// Cache persists tokens between processes. By default, the credential caches tokens only in memory.
+	Cache Cache
+
+	// CodeVerifier is the PKCE code verifier from which the application derived the code_challenge
+	// of its authorization request. Set it when the authorization request included a code challenge.
+	CodeVerifier string
+
+	// DisableInstanceDiscovery allows disconnected cloud solutions to skip instance discovery for unknown authority hosts.
+	DisableInstanceDiscovery bool
+
+	// SendCertificateChain applies only when the credential is configured to authenticate with a certificate.
+	// This setting controls whether the credential sends the public certificate chain in the x5c header of each
+	// token request's JWT. This is required for, and only used in, Subject Name/Issuer (SNI) authentication.
+	SendCertificateChain bool
+}
+
+// NewAuthorizationCodeCredentialFromCertificate constructs an AuthorizationCodeCredential that authenticates the
+// application with a certificate. redirectURL must be the redirect URL of the request that returned authCode.
+// See [ParseCertificates] for help loading a certificate.
+func NewAuthorizationCodeCredentialFromCertificate(tenantID, clientID, authCode, redirectURL string, certs []*x509.Certificate, key crypto.PrivateKey, options *AuthorizationCodeCredentialOptions) (*AuthorizationCodeCredential, error) {
+	if len(certs) == 0 {
+		return nil, errors.New("at least one certificate is required")
+	}
+	cred, err := confidential.NewCredFromCertChain(certs, key)
+	if err != nil {
+		return nil, err
+	}
+	return newAuthorizationCodeCredential(tenantID, clientID, authCode, redirectURL, cred, options)
+}
+
+// NewAuthorizationCodeCredentialFromSecret constructs an AuthorizationCodeCredential that authenticates the
+// application with a client secret. redirectURL must be the redirect URL of the request that returned authCode.
+func NewAuthorizationCodeCredentialFromSecret(tenantID, clientID, authCode, redirectURL, clientSecret string, options *AuthorizationCodeCredentialOptions) (*AuthorizationCodeCredential, error) {
+	cred, err := confidential.NewCredFromSecret(clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	return newAuthorizationCodeCredential(tenantID, clientID, authCode, redirectURL, cred, options)
+}
+
+func newAuthorizationCodeCredential(tenantID, clientID, authCode, redirectURL string, cred confidential.Credential, options *AuthorizationCodeCredentialOptions) (*AuthorizationCodeCredential, error) {
+	if authCode == "" {
+		return nil, errors.New(credNameAuthCode + ": authCode is required")
+	}
+	if options == nil {
+		options = &AuthorizationCodeCredentialOptions{}
+	}
+	opts := []confidential.Option{}
+	if options.SendCertificateChain {
+		opts = append(opts, confidential.WithX5C())
+	}
+	opts = append(opts, confidential.WithInstanceDiscovery(!options.DisableInstanceDiscovery), confidential.WithAccessor(newCacheAccessor(options.Cache)))
+	c, err := getConfidentialClient(clientID, tenantID, cred, &options.ClientOptions, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthorizationCodeCredential{
+		authCode:     authCode,
+		client:       c,
+		codeVerifier: options.CodeVerifier,
+		mtx:          &sync.Mutex{},
+		redirectURL:  redirectURL,
+	}, nil
+}
+
+// GetToken requests an access token from Azure Active Directory. This method is called automatically by Azure SDK clients.
+func (c *AuthorizationCodeCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	if len(opts.Scopes) == 0 {
+		return azcore.AccessToken{}, errors.New(credNameAuthCode + ": GetToken() requires at least one scope")
+	}
+	// serialize authentication because an authorization code can be redeemed only once
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.authCode == "" {
+		ar, err := c.client.AcquireTokenSilent(ctx, opts.Scopes, confidential.WithSilentAccount(c.account))
+		if err != nil {
+			return azcore.AccessToken{}, newAuthenticationFailedErrorFromMSALError(credNameAuthCode, err)
+		}
+		logGetTokenSuccess(c, opts)
+		return azcore.AccessToken{Token: ar.AccessToken, ExpiresOn: ar.ExpiresOn.UTC()}, nil
+	}
+	var o []confidential.AcquireByAuthCodeOption
+	if c.codeVerifier != "" {
+		// MSAL sends this value as the code_verifier parameter
+		o = append(o, confidential.WithChallenge(c.codeVerifier))
+	}
+	ar, err := c.client.AcquireTokenByAuthCode(ctx, c.authCode, c.redirectURL, opts.Scopes, o...)
+	if err != nil {
+		return azcore.AccessToken{}, newAuthenticationFailedErrorFromMSALError(credNameAuthCode, err)
+	}
+	// the code is spent; later calls get tokens for this account from the cache
+	c.account = ar.Account
+	c.authCode = ""
+	logGetTokenSuccess(c, opts)
+	return azcore.AccessToken{Token: ar.AccessToken, ExpiresOn: ar.ExpiresOn.UTC()}, nil
+}
+
+var _ azcore.TokenCredential = (*AuthorizationCodeCredential)(nil)
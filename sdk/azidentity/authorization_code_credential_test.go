@@ -0,0 +1,189 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azidentity
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/mock"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/confidential"
+)
+
+const (
+	fakeAuthCode     = "fake-auth-code"
+	fakeCodeVerifier = "fake-code-verifier"
+	fakeRedirectURL  = "https://localhost/redirect"
+)
+
+// authCodeTokenResponse returns a token response including the refresh token and
+// account information Azure AD returns when redeeming an authorization code
+func authCodeTokenResponse(accessToken string) []byte {
+	enc := base64.RawURLEncoding.EncodeToString
+	clientInfo := enc([]byte(fmt.Sprintf(`{"uid":"uid","utid":"%s"}`, fakeTenantID)))
+	idToken := enc([]byte(`{"alg":"none"}`)) + "." + enc([]byte(fmt.Sprintf(
+		`{"aud":"%s","iss":"https://login.microsoftonline.com/%s/v2.0","oid":"uid","preferred_username":"%s","sub":"sub","tid":"%s"}`,
+		fakeClientID, fakeTenantID, fakeUsername, fakeTenantID,
+	))) + "."
+	return []byte(fmt.Sprintf(
+		`{"access_token":"%s","client_info":"%s","expires_in":3600,"id_token":"%s","refresh_token":"fake-refresh-token","token_type":"Bearer"}`,
+		accessToken, clientInfo, idToken,
+	))
+}
+
+func TestAuthorizationCodeCredential(t *testing.T) {
+	for _, verifier := range []string{"", fakeCodeVerifier} {
+		t.Run(fmt.Sprintf("verifier=%q", verifier), func(t *testing.T) {
+			redeemed := func(req *http.Request) bool {
+				if err := req.ParseForm(); err != nil {
+					t.Fatal(err)
+				}
+				for k, v := range map[string]string{
+					"code":          fakeAuthCode,
+					"code_verifier": verifier,
+					"grant_type":    "authorization_code",
+					"redirect_uri":  fakeRedirectURL,
+				} {
+					if actual := req.PostForm.Get(k); actual != v {
+						t.Fatalf("expected %s %q, got %q", k, v, actual)
+					}
+				}
+				return true
+			}
+			refreshed := func(req *http.Request) bool {
+				if err := req.ParseForm(); err != nil {
+					t.Fatal(err)
+				}
+				if actual := req.PostForm.Get("grant_type"); actual != "refresh_token" {
+					t.Fatalf("expected a refresh token request, got grant_type %q", actual)
+				}
+				if req.PostForm.Get("code") != "" {
+					t.Fatal("the credential should redeem the authorization code only once")
+				}
+				return true
+			}
+			srv, close := mock.NewServer(mock.WithTransformAllRequestsToTestServerUrl())
+			defer close()
+			// redeeming a code requires only tenant discovery
+			srv.AppendResponse(mock.WithBody(tenantDiscoveryResponse))
+			// each predicated response is followed by the response for requests not matching the predicate
+			srv.AppendResponse(mock.WithPredicate(redeemed), mock.WithBody(authCodeTokenResponse(tokenValue)))
+			srv.AppendResponse(mock.WithStatusCode(http.StatusBadRequest))
+			// silent authentication validates the authority before redeeming the refresh token
+			srv.AppendResponse(mock.WithBody(instanceDiscoveryResponse))
+			srv.AppendResponse(mock.WithPredicate(refreshed), mock.WithBody(authCodeTokenResponse("refreshed")))
+			srv.AppendResponse(mock.WithStatusCode(http.StatusBadRequest))
+
+			cred, err := NewAuthorizationCodeCredentialFromSecret(fakeTenantID, fakeClientID, fakeAuthCode, fakeRedirectURL, "secret", &AuthorizationCodeCredentialOptions{
+				ClientOptions: azcore.ClientOptions{Transport: srv},
+				CodeVerifier:  verifier,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, test := range []struct{ scope, token string }{
+				{liveTestScope, tokenValue},
+				// the credential should get this token from the cache
+				{liveTestScope, tokenValue},
+				// this token isn't cached, so the credential should redeem the refresh token
+				{"https://storage.azure.com/.default", "refreshed"},
+			} {
+				tk, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{test.scope}})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tk.Token != test.token {
+					t.Fatalf("expected %q, got %q", test.token, tk.Token)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthorizationCodeCredential_Certificate(t *testing.T) {
+	test := allCertTests[0]
+	if _, err := NewAuthorizationCodeCredentialFromCertificate(fakeTenantID, fakeClientID, fakeAuthCode, fakeRedirectURL, nil, test.key, nil); err == nil {
+		t.Fatal("expected an error for missing certificates")
+	}
+	cred, err := NewAuthorizationCodeCredentialFromCertificate(fakeTenantID, fakeClientID, fakeAuthCode, fakeRedirectURL, test.certs, test.key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred.client = fakeConfidentialClient{ar: confidential.AuthResult{AccessToken: tokenValue, ExpiresOn: time.Now().Add(time.Hour)}, silentAuth: true}
+	testGetTokenSuccess(t, cred)
+}
+
+func TestAuthorizationCodeCredential_Errors(t *testing.T) {
+	if _, err := NewAuthorizationCodeCredentialFromSecret(fakeTenantID, fakeClientID, "", fakeRedirectURL, "secret", nil); err == nil {
+		t.Fatal("expected an error for an empty authorization code")
+	}
+	cred, err := NewAuthorizationCodeCredentialFromSecret(fakeTenantID, fakeClientID, fakeAuthCode, fakeRedirectURL, "secret", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred.client = fakeConfidentialClient{err: errors.New("invalid_grant")}
+	_, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{liveTestScope}})
+	var afe *AuthenticationFailedError
+	if !errors.As(err, &afe) {
+		t.Fatalf("expected AuthenticationFailedError, got %T", err)
+	}
+	// redemption failed, so the credential should try again
+	cred.client = fakeConfidentialClient{ar: confidential.AuthResult{AccessToken: tokenValue, ExpiresOn: time.Now().Add(time.Hour)}}
+	if _, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{liveTestScope}}); err != nil {
+		t.Fatal(err)
+	}
+	// the code is spent, and silent authentication fails
+	_, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{liveTestScope}})
+	if !errors.As(err, &afe) {
+		t.Fatalf("expected AuthenticationFailedError, got %T", err)
+	}
+}
+
+func TestAuthorizationCodeCredential_Concurrency(t *testing.T) {
+	cred, err := NewAuthorizationCodeCredentialFromSecret(fakeTenantID, fakeClientID, fakeAuthCode, fakeRedirectURL, "secret", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	redemptions := 0
+	cred.client = &countingConfidentialClient{
+		fakeConfidentialClient: fakeConfidentialClient{ar: confidential.AuthResult{AccessToken: tokenValue, ExpiresOn: time.Now().Add(time.Hour)}, silentAuth: true},
+		redemptions:            &redemptions,
+	}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{liveTestScope}}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if redemptions != 1 {
+		t.Fatalf("expected 1 redemption, got %d", redemptions)
+	}
+}
+
+// countingConfidentialClient counts calls to AcquireTokenByAuthCode
+type countingConfidentialClient struct {
+	fakeConfidentialClient
+	redemptions *int
+}
+
+func (c *countingConfidentialClient) AcquireTokenByAuthCode(ctx context.Context, code string, redirectURI string, scopes []string, options ...confidential.AcquireByAuthCodeOption) (confidential.AuthResult, error) {
+	*c.redemptions++
+	return c.fakeConfidentialClient.AcquireTokenByAuthCode(ctx, code, redirectURI, scopes, options...)
+}
@@ -229,7 +229,12 @@ func Test_DefaultAuthorityHost(t *testing.T) {
 
 func Test_GetTokenRequiresScopes(t *testing.T) {
 	for _, ctor := range []func() (azcore.TokenCredential, error){
+		func() (azcore.TokenCredential, error) {
+			return NewAuthorizationCodeCredentialFromSecret("tenantID", "clientID", "authCode", "https://localhost", "secret", nil)
+		},
 		func() (azcore.TokenCredential, error) { return NewAzureCLICredential(nil) },
+		func() (azcore.TokenCredential, error) { return NewAzureDeveloperCLICredential(nil) },
+		func() (azcore.TokenCredential, error) { return NewAzurePowerShellCredential(nil) },
 		func() (azcore.TokenCredential, error) {
 			return NewClientCertificateCredential("tenantID", "clientID", allCertTests[0].certs, allCertTests[0].key, nil)
 		},
@@ -346,6 +351,9 @@ var _ confidentialClient = (*fakeConfidentialClient)(nil)
 // ==================================================================================================================================
 
 type fakePublicClient struct {
+	// accounts is returned by Accounts()
+	accounts []public.Account
+
 	// set ar to have all API calls return the provided AuthResult
 	ar public.AuthResult
 
@@ -392,4 +400,8 @@ func (f fakePublicClient) AcquireTokenInteractive(ctx context.Context, scopes []
 	return f.returnResult()
 }
 
+func (f fakePublicClient) Accounts() []public.Account {
+	return f.accounts
+}
+
 var _ publicClient = (*fakePublicClient)(nil)
@@ -0,0 +1,113 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azidentity
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+var mockAzdTokenProviderSuccess = func(ctx context.Context, scopes []string, tenantID string) ([]byte, error) {
+	return []byte(`{
+  "token": "mocktoken",
+  "expiresOn": "2001-02-03T04:05:06Z"
+}
+`), nil
+}
+
+func TestAzureDeveloperCLICredential_GetTokenSuccess(t *testing.T) {
+	cred, err := NewAzureDeveloperCLICredential(&AzureDeveloperCLICredentialOptions{tokenProvider: mockAzdTokenProviderSuccess})
+	if err != nil {
+		t.Fatal(err)
+	}
+	at, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{liveTestScope}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if at.Token != "mocktoken" {
+		t.Fatalf("unexpected access token %q", at.Token)
+	}
+	expected := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+	if actual := at.ExpiresOn; !actual.Equal(expected) || actual.Location() != time.UTC {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestAzureDeveloperCLICredential_GetTokenInvalidToken(t *testing.T) {
+	for _, output := range []string{"not json", `{"token":"mocktoken","expiresOn":"2001-02-03 04:05:06"}`} {
+		cred, err := NewAzureDeveloperCLICredential(&AzureDeveloperCLICredentialOptions{
+			tokenProvider: func(context.Context, []string, string) ([]byte, error) { return []byte(output), nil },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{liveTestScope}}); err == nil {
+			t.Fatalf("expected an error for output %q", output)
+		}
+	}
+}
+
+func TestAzureDeveloperCLICredential_ProviderError(t *testing.T) {
+	expected := newCredentialUnavailableError(credNameAzureDeveloperCLI, "Azure Developer CLI not found on path")
+	cred, err := NewAzureDeveloperCLICredential(&AzureDeveloperCLICredentialOptions{
+		tokenProvider: func(context.Context, []string, string) ([]byte, error) { return nil, expected },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{liveTestScope}})
+	if !errors.Is(err, expected) {
+		t.Fatalf("expected %v, got %v", expected, err)
+	}
+}
+
+func TestAzureDeveloperCLICredential_ScopesAndTenantID(t *testing.T) {
+	expectedScopes := []string{"scope1", liveTestScope}
+	expectedTenant := "expected-tenant-id"
+	called := false
+	cred, err := NewAzureDeveloperCLICredential(&AzureDeveloperCLICredentialOptions{
+		TenantID: expectedTenant,
+		tokenProvider: func(ctx context.Context, scopes []string, tenantID string) ([]byte, error) {
+			called = true
+			if tenantID != expectedTenant {
+				t.Fatal("Unexpected tenant ID: " + tenantID)
+			}
+			// azd accepts v2 scopes, so the credential shouldn't convert them to a resource
+			if len(scopes) != len(expectedScopes) || scopes[0] != expectedScopes[0] || scopes[1] != expectedScopes[1] {
+				t.Fatalf("unexpected scopes %v", scopes)
+			}
+			return mockAzdTokenProviderSuccess(ctx, scopes, tenantID)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: expectedScopes}); err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Fatal("token provider wasn't called")
+	}
+	if _, err = NewAzureDeveloperCLICredential(&AzureDeveloperCLICredentialOptions{TenantID: "invalid tenant"}); err == nil {
+		t.Fatal("expected an error for an invalid tenant ID")
+	}
+}
+
+func TestAzureDeveloperCLICredential_InvalidScope(t *testing.T) {
+	cred, err := NewAzureDeveloperCLICredential(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the default token provider should reject the scope before invoking azd
+	if _, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{"scope; rm -rf /"}}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
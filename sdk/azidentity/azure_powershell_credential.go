@@ -0,0 +1,206 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azidentity
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const (
+	credNameAzurePowerShell = "AzurePowerShellCredential"
+	// noAzAccountModule is written by the token script when the Az.Accounts module isn't installed
+	noAzAccountModule = "NoAzAccountModule"
+)
+
+// used by tests to fake invoking PowerShell
+type azurePowerShellTokenProvider func(ctx context.Context, resource string, tenantID string) ([]byte, error)
+
+// AzurePowerShellCredentialOptions contains optional parameters for AzurePowerShellCredential.
+type AzurePowerShellCredentialOptions struct {
+	// TenantID identifies the tenant the credential should authenticate in. Defaults to the tenant of
+	// the Azure PowerShell context, which is typically the home tenant of the logged in user.
+	TenantID string
+
+	tokenProvider azurePowerShellTokenProvider
+}
+
+// init returns an instance of AzurePowerShellCredentialOptions initialized with default values.
+func (o *AzurePowerShellCredentialOptions) init() {
+	if o.tokenProvider == nil {
+		o.tokenProvider = defaultAzurePowerShellTokenProvider
+	}
+}
+
+// AzurePowerShellCredential authenticates as the identity logged in to Azure PowerShell, that is the account
+// of the Az.Accounts module's Connect-AzAccount cmdlet. It requires PowerShell 7 ("pwsh") or, on Windows,
+// Windows PowerShell, and Az.Accounts 2.2.0 or later.
+type AzurePowerShellCredential struct {
+	tokenProvider azurePowerShellTokenProvider
+	tenantID      string
+}
+
+// NewAzurePowerShellCredential constructs an AzurePowerShellCredential. Pass nil to accept default options.
+func NewAzurePowerShellCredential(options *AzurePowerShellCredentialOptions) (*AzurePowerShellCredential, error) {
+	cp := AzurePowerShellCredentialOptions{}
+	if options != nil {
+		cp = *options
+	}
+	if cp.TenantID != "" && !validTenantID(cp.TenantID) {
+		return nil, errors.New(tenantIDValidationErr)
+	}
+	cp.init()
+	return &AzurePowerShellCredential{
+		tokenProvider: cp.tokenProvider,
+		tenantID:      cp.TenantID,
+	}, nil
+}
+
+// GetToken requests a token from Azure PowerShell. This credential doesn't cache tokens, so every call invokes
+// PowerShell. This method is called automatically by Azure SDK clients.
+func (c *AzurePowerShellCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	if len(opts.Scopes) != 1 {
+		return azcore.AccessToken{}, errors.New(credNameAzurePowerShell + ": GetToken() requires exactly one scope")
+	}
+	// Get-AzAccessToken expects an AAD v1 resource, not a v2 scope
+	resource := strings.TrimSuffix(opts.Scopes[0], defaultSuffix)
+	b, err := c.tokenProvider(ctx, resource, c.tenantID)
+	if err != nil {
+		return azcore.AccessToken{}, err
+	}
+	at, err := c.createAccessToken(b)
+	if err != nil {
+		return azcore.AccessToken{}, err
+	}
+	logGetTokenSuccess(c, opts)
+	return at, nil
+}
+
+// azurePowerShellScript gets a token with Get-AzAccessToken. It writes the expiration as Unix seconds because
+// PowerShell's date format depends on the culture. Newer versions of Az.Accounts return the token as a SecureString.
+const azurePowerShellScript = `$ErrorActionPreference = 'Stop'
+$m = Import-Module Az.Accounts -MinimumVersion 2.2.0 -PassThru -ErrorAction SilentlyContinue
+if (!$m) {
+	Write-Output '` + noAzAccountModule + `'
+	exit
+}
+$params = @{ 'ResourceUrl' = '%s' }
+if ('%s') {
+	$params['TenantId'] = '%s'
+}
+$token = Get-AzAccessToken @params
+$t = $token.Token
+if ($t -is [System.Security.SecureString]) {
+	$t = [System.Net.NetworkCredential]::new('', $t).Password
+}
+@{ 'Token' = $t; 'ExpiresOn' = $token.ExpiresOn.ToUnixTimeSeconds() } | ConvertTo-Json
+`
+
+var defaultAzurePowerShellTokenProvider azurePowerShellTokenProvider = func(ctx context.Context, resource string, tenantID string) ([]byte, error) {
+	// the resource and tenant are interpolated into a script, so they must not contain quotes
+	match, err := regexp.MatchString("^[0-9a-zA-Z-.:/]+$", resource)
+	if err != nil {
+		return nil, err
+	}
+	if !match {
+		return nil, fmt.Errorf(`%s: unexpected scope "%s". Only alphanumeric characters and ".", ":", "-", and "/" are allowed`, credNameAzurePowerShell, resource)
+	}
+
+	// set a default timeout for this authentication iff the application hasn't done so already
+	var cancel context.CancelFunc
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		ctx, cancel = context.WithTimeout(ctx, timeoutCLIRequest)
+		defer cancel()
+	}
+
+	commandLine := "-NoProfile -NonInteractive -EncodedCommand " + encodePowerShellScript(fmt.Sprintf(azurePowerShellScript, resource, tenantID, tenantID))
+	var cliCmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		dir := os.Getenv("SYSTEMROOT")
+		if dir == "" {
+			return nil, newCredentialUnavailableError(credNameAzurePowerShell, "environment variable 'SYSTEMROOT' has no value")
+		}
+		// prefer PowerShell 7 but fall back to Windows PowerShell, which Windows includes
+		shell := "pwsh"
+		if _, err := exec.LookPath(shell); err != nil {
+			shell = "powershell"
+		}
+		cliCmd = exec.CommandContext(ctx, "cmd.exe", "/c", shell+" "+commandLine)
+		cliCmd.Dir = dir
+	} else {
+		cliCmd = exec.CommandContext(ctx, "/bin/sh", "-c", "pwsh "+commandLine)
+		cliCmd.Dir = "/bin"
+	}
+	cliCmd.Env = os.Environ()
+	var stderr bytes.Buffer
+	cliCmd.Stderr = &stderr
+
+	output, err := cliCmd.Output()
+	if err != nil {
+		msg := stderr.String()
+		var exErr *exec.ExitError
+		if errors.As(err, &exErr) && exErr.ExitCode() == 127 || strings.HasPrefix(msg, "'pwsh' is not recognized") {
+			msg = "PowerShell not found on path"
+		} else if strings.Contains(msg, "Connect-AzAccount") {
+			msg = `please run "Connect-AzAccount" to set up account`
+		}
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, newCredentialUnavailableError(credNameAzurePowerShell, msg)
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(output), []byte(noAzAccountModule)) {
+		return nil, newCredentialUnavailableError(credNameAzurePowerShell, "Az.Accounts module >= 2.2.0 is not installed")
+	}
+	return output, nil
+}
+
+// encodePowerShellScript encodes script for PowerShell's -EncodedCommand parameter,
+// which is base64 encoded UTF-16LE. This spares quoting the script for the shell.
+func encodePowerShellScript(script string) string {
+	u := utf16.Encode([]rune(script))
+	b := make([]byte, 2*len(u))
+	for i, r := range u {
+		binary.LittleEndian.PutUint16(b[2*i:], r)
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func (c *AzurePowerShellCredential) createAccessToken(tk []byte) (azcore.AccessToken, error) {
+	t := struct {
+		Token     string `json:"Token"`
+		ExpiresOn int64  `json:"ExpiresOn"`
+	}{}
+	err := json.Unmarshal(tk, &t)
+	if err != nil {
+		return azcore.AccessToken{}, err
+	}
+	if t.Token == "" {
+		return azcore.AccessToken{}, newCredentialUnavailableError(credNameAzurePowerShell, "Azure PowerShell returned no token")
+	}
+	return azcore.AccessToken{
+		Token:     t.Token,
+		ExpiresOn: time.Unix(t.ExpiresOn, 0).UTC(),
+	}, nil
+}
+
+var _ azcore.TokenCredential = (*AzurePowerShellCredential)(nil)
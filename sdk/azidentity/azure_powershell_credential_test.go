@@ -0,0 +1,120 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azidentity
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+var mockAzurePowerShellTokenProviderSuccess = func(ctx context.Context, resource string, tenantID string) ([]byte, error) {
+	return []byte(`{
+  "Token": "mocktoken",
+  "ExpiresOn": 981173106
+}
+`), nil
+}
+
+func TestAzurePowerShellCredential_GetTokenSuccess(t *testing.T) {
+	cred, err := NewAzurePowerShellCredential(&AzurePowerShellCredentialOptions{tokenProvider: mockAzurePowerShellTokenProviderSuccess})
+	if err != nil {
+		t.Fatal(err)
+	}
+	at, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{liveTestScope}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if at.Token != "mocktoken" {
+		t.Fatalf("unexpected access token %q", at.Token)
+	}
+	expected := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+	if actual := at.ExpiresOn; !actual.Equal(expected) || actual.Location() != time.UTC {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestAzurePowerShellCredential_GetTokenInvalidToken(t *testing.T) {
+	for _, output := range []string{"not json", `{"ExpiresOn": 981173106}`} {
+		cred, err := NewAzurePowerShellCredential(&AzurePowerShellCredentialOptions{
+			tokenProvider: func(context.Context, string, string) ([]byte, error) { return []byte(output), nil },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{liveTestScope}}); err == nil {
+			t.Fatalf("expected an error for output %q", output)
+		}
+	}
+}
+
+func TestAzurePowerShellCredential_ProviderError(t *testing.T) {
+	expected := errors.New("provider failure message")
+	cred, err := NewAzurePowerShellCredential(&AzurePowerShellCredentialOptions{
+		tokenProvider: func(context.Context, string, string) ([]byte, error) { return nil, expected },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{liveTestScope}})
+	if !errors.Is(err, expected) {
+		t.Fatalf("expected %v, got %v", expected, err)
+	}
+}
+
+func TestAzurePowerShellCredential_ResourceAndTenantID(t *testing.T) {
+	expectedTenant := "expected-tenant-id"
+	called := false
+	cred, err := NewAzurePowerShellCredential(&AzurePowerShellCredentialOptions{
+		TenantID: expectedTenant,
+		tokenProvider: func(ctx context.Context, resource, tenantID string) ([]byte, error) {
+			called = true
+			if tenantID != expectedTenant {
+				t.Fatal("Unexpected tenant ID: " + tenantID)
+			}
+			if resource != "https://management.core.windows.net" {
+				t.Fatal("unexpected resource: " + resource)
+			}
+			return mockAzurePowerShellTokenProviderSuccess(ctx, resource, tenantID)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{"https://management.core.windows.net/.default"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Fatal("token provider wasn't called")
+	}
+	if _, err = NewAzurePowerShellCredential(&AzurePowerShellCredentialOptions{TenantID: "'; exit"}); err == nil {
+		t.Fatal("expected an error for an invalid tenant ID")
+	}
+}
+
+func TestEncodePowerShellScript(t *testing.T) {
+	script := "Write-Output 'é'"
+	b, err := base64.StdEncoding.DecodeString(encodePowerShellScript(script))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b)%2 != 0 {
+		t.Fatalf("expected UTF-16, got %d bytes", len(b))
+	}
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = uint16(b[2*i]) | uint16(b[2*i+1])<<8
+	}
+	if actual := string(utf16.Decode(u)); actual != script {
+		t.Fatalf("expected %q, got %q", script, actual)
+	}
+}
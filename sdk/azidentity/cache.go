@@ -0,0 +1,231 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azidentity
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/log"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+)
+
+const (
+	defaultFileCacheLockTimeout = 10 * time.Second
+	fileCacheLockRetryDelay     = 10 * time.Millisecond
+	// fileCacheLockStaleAge is the age at which a lock is considered abandoned. Processes hold
+	// the lock only while reading or writing the cache file, so a lock this old is stale.
+	fileCacheLockStaleAge = time.Minute
+)
+
+// Cache persists a credential's tokens outside the process so that credentials in later processes can authenticate
+// silently with them. For example, a command line tool configuring InteractiveBrowserCredential with a Cache prompts
+// users to sign in only when no cached token, including refresh tokens, is valid. The data is opaque and contains
+// secrets, so implementations should protect it. A Cache may be shared by credentials of the same user.
+type Cache interface {
+	// Load returns the data most recently passed to Store, or nil when there is none.
+	Load() ([]byte, error)
+	// Store replaces the cache's data.
+	Store(data []byte) error
+}
+
+// FileCacheOptions contains optional parameters for NewFileCache.
+type FileCacheOptions struct {
+	// AllowUnencryptedStorage permits storing tokens in plaintext when Encrypt and Decrypt aren't set. The cache
+	// file is readable only by its owner, but any process running as that user can read the tokens it contains.
+	AllowUnencryptedStorage bool
+
+	// Decrypt decrypts data encrypted by Encrypt. Set both Encrypt and Decrypt, or neither.
+	Decrypt func([]byte) ([]byte, error)
+
+	// Encrypt encrypts data before the cache writes it to the file, for example with a key from
+	// the operating system's secret store. Set both Encrypt and Decrypt, or neither.
+	Encrypt func([]byte) ([]byte, error)
+
+	// LockTimeout is how long to wait for another process to finish reading or writing the cache file.
+	// Defaults to 10 seconds.
+	LockTimeout time.Duration
+}
+
+// FileCache is a Cache that stores data in a file. It locks the file while reading or writing it,
+// so it can be shared by multiple processes.
+type FileCache struct {
+	decrypt, encrypt func([]byte) ([]byte, error)
+	lockTimeout      time.Duration
+	path             string
+}
+
+// NewFileCache creates a FileCache storing data in the file at path, which is created as needed. When path is
+// empty, the cache stores data in the user's cache directory. Pass nil for options to accept defaults, however
+// storing unencrypted data requires setting options.AllowUnencryptedStorage.
+func NewFileCache(path string, options *FileCacheOptions) (*FileCache, error) {
+	if options == nil {
+		options = &FileCacheOptions{}
+	}
+	if (options.Encrypt == nil) != (options.Decrypt == nil) {
+		return nil, errors.New("FileCacheOptions.Encrypt and FileCacheOptions.Decrypt must be set together")
+	}
+	if options.Encrypt == nil && !options.AllowUnencryptedStorage {
+		return nil, errors.New("storing unencrypted data requires FileCacheOptions.AllowUnencryptedStorage")
+	}
+	if path == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(dir, "azidentity", "msal.cache")
+	}
+	c := FileCache{
+		decrypt:     options.Decrypt,
+		encrypt:     options.Encrypt,
+		lockTimeout: options.LockTimeout,
+		path:        path,
+	}
+	if c.lockTimeout <= 0 {
+		c.lockTimeout = defaultFileCacheLockTimeout
+	}
+	return &c, nil
+}
+
+// Load returns the data in the cache file, or nil when the file doesn't exist.
+func (c *FileCache) Load() ([]byte, error) {
+	unlock, err := c.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if c.decrypt != nil && len(data) > 0 {
+		data, err = c.decrypt(data)
+	}
+	return data, err
+}
+
+// Store replaces the data in the cache file.
+func (c *FileCache) Store(data []byte) error {
+	var err error
+	if c.encrypt != nil {
+		if data, err = c.encrypt(data); err != nil {
+			return err
+		}
+	}
+	unlock, err := c.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	// write a temporary file and rename it so readers never see a partially written file
+	f, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+// lock acquires the cache file's lock, which is a file created exclusively next to the cache file, and returns a
+// function that releases it.
+func (c *FileCache) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return nil, err
+	}
+	lockPath := c.path + ".lock"
+	deadline := time.Now().Add(c.lockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, _ = fmt.Fprint(f, os.Getpid())
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if fi, err := os.Stat(lockPath); err == nil && time.Since(fi.ModTime()) > fileCacheLockStaleAge {
+			log.Writef(EventAuthentication, "removing abandoned token cache lock %s", lockPath)
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for token cache lock %s", lockPath)
+		}
+		time.Sleep(fileCacheLockRetryDelay)
+	}
+}
+
+// cacheAccessor adapts a Cache to MSAL's cache.ExportReplace. MSAL passes the entire cache to Export,
+// so cacheAccessor ignores the suggested partition keys.
+type cacheAccessor struct {
+	c Cache
+}
+
+// newCacheAccessor returns an accessor for c, or nil when c is nil, in which case MSAL caches tokens in memory
+func newCacheAccessor(c Cache) cache.ExportReplace {
+	if c == nil {
+		return nil
+	}
+	return cacheAccessor{c}
+}
+
+func (a cacheAccessor) Replace(u cache.Unmarshaler, _ string) {
+	data, err := a.c.Load()
+	if err == nil && len(data) > 0 {
+		err = u.Unmarshal(data)
+	}
+	if err != nil {
+		log.Writef(EventAuthentication, "failed to load persistent token cache: %v", err)
+	}
+}
+
+func (a cacheAccessor) Export(m cache.Marshaler, _ string) {
+	data, err := m.Marshal()
+	if err == nil {
+		err = a.c.Store(data)
+	}
+	if err != nil {
+		log.Writef(EventAuthentication, "failed to store persistent token cache: %v", err)
+	}
+}
+
+// cachedAccount returns the account in the client's cache having the specified username or, when username is
+// empty, the only cached account. Public client credentials use this account to authenticate silently with tokens
+// a persistent cache loaded. It returns a zero account when the cache contains no such account.
+func cachedAccount(client publicClient, username string) public.Account {
+	var match public.Account
+	n := 0
+	for _, a := range client.Accounts() {
+		if username == "" || strings.EqualFold(a.PreferredUsername, username) {
+			match = a
+			n++
+		}
+	}
+	if n != 1 {
+		// choosing one of several accounts could authenticate the wrong user
+		return public.Account{}
+	}
+	return match
+}
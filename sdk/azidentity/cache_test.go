@@ -0,0 +1,243 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azidentity
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/mock"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+)
+
+// xor is a toy encryption function for testing
+func xor(data []byte) ([]byte, error) {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[i] = b ^ 0x5a
+	}
+	return out, nil
+}
+
+func TestNewFileCacheOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache")
+	for _, o := range []*FileCacheOptions{nil, {}, {Encrypt: xor}, {Decrypt: xor}, {AllowUnencryptedStorage: true, Encrypt: xor}} {
+		if _, err := NewFileCache(path, o); err == nil {
+			t.Fatalf("expected an error for options %+v", o)
+		}
+	}
+	for _, o := range []*FileCacheOptions{{AllowUnencryptedStorage: true}, {Encrypt: xor, Decrypt: xor}} {
+		if _, err := NewFileCache(path, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dir", "cache")
+	c, err := NewFileCache(path, &FileCacheOptions{Encrypt: xor, Decrypt: xor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := c.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != nil {
+		t.Fatalf("expected no data, got %q", data)
+	}
+	expected := []byte(`{"secret":"value"}`)
+	if err = c.Store(expected); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("secret")) {
+		t.Fatal("cache file contains plaintext")
+	}
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := fi.Mode().Perm(); perm != 0600 {
+			t.Fatalf("expected permissions 0600, got %o", perm)
+		}
+	}
+	// another instance, as in another process, should load the data
+	other, err := NewFileCache(path, &FileCacheOptions{Encrypt: xor, Decrypt: xor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err = other.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, expected) {
+		t.Fatalf("expected %q, got %q", expected, data)
+	}
+	if _, err = os.Stat(path + ".lock"); err == nil {
+		t.Fatal("the cache didn't release its lock")
+	}
+}
+
+func TestFileCacheLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache")
+	c, err := NewFileCache(path, &FileCacheOptions{AllowUnencryptedStorage: true, LockTimeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// simulate another process holding the lock
+	lockPath := path + ".lock"
+	if err = os.WriteFile(lockPath, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Store([]byte("data")); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+	// an old lock was abandoned by a process that exited
+	old := time.Now().Add(-2 * fileCacheLockStaleAge)
+	if err = os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Store([]byte("data")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileCacheConcurrency(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache")
+	wg := sync.WaitGroup{}
+	errs := make(chan error, 20)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c, err := NewFileCache(path, &FileCacheOptions{AllowUnencryptedStorage: true})
+			if err == nil {
+				err = c.Store([]byte(fmt.Sprint("data", i)))
+			}
+			if err == nil {
+				var data []byte
+				if data, err = c.Load(); err == nil && !bytes.HasPrefix(data, []byte("data")) {
+					err = fmt.Errorf("unexpected data %q", data)
+				}
+			}
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPersistentCache(t *testing.T) {
+	c, err := NewFileCache(filepath.Join(t.TempDir(), "cache"), &FileCacheOptions{Encrypt: xor, Decrypt: xor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenRequests := 0
+	srv, close := mock.NewServer(mock.WithTransformAllRequestsToTestServerUrl())
+	defer close()
+	transport := countingTransport{srv: srv, counter: &tokenRequests}
+	for i := 0; i < 2; i++ {
+		srv.AppendResponse(mock.WithBody(instanceDiscoveryResponse))
+		srv.AppendResponse(mock.WithBody(tenantDiscoveryResponse))
+		if i == 0 {
+			srv.AppendResponse(mock.WithBody(accessTokenRespSuccess))
+		}
+		// each credential represents a different process
+		cred, err := NewClientSecretCredential(fakeTenantID, fakeClientID, "secret", &ClientSecretCredentialOptions{
+			Cache:         c,
+			ClientOptions: azcore.ClientOptions{Transport: &transport},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tk, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{liveTestScope}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tk.Token != tokenValue {
+			t.Fatalf("unexpected token %q", tk.Token)
+		}
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("expected 1 token request, got %d", tokenRequests)
+	}
+}
+
+// countingTransport counts token requests sent to srv
+type countingTransport struct {
+	srv     *mock.Server
+	counter *int
+}
+
+func (c *countingTransport) Do(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/token") {
+		*c.counter++
+	}
+	return c.srv.Do(req)
+}
+
+func TestCachedAccount(t *testing.T) {
+	a := public.Account{HomeAccountID: "a", PreferredUsername: "a@contoso.com"}
+	b := public.Account{HomeAccountID: "b", PreferredUsername: "b@contoso.com"}
+	for _, test := range []struct {
+		accounts []public.Account
+		username string
+		expected string
+	}{
+		{accounts: nil, expected: ""},
+		{accounts: []public.Account{a}, expected: "a"},
+		{accounts: []public.Account{a}, username: "A@contoso.com", expected: "a"},
+		{accounts: []public.Account{a}, username: "b@contoso.com", expected: ""},
+		{accounts: []public.Account{a, b}, expected: ""},
+		{accounts: []public.Account{a, b}, username: "b@contoso.com", expected: "b"},
+	} {
+		actual := cachedAccount(fakePublicClient{accounts: test.accounts}, test.username)
+		if actual.HomeAccountID != test.expected {
+			t.Fatalf("expected %q, got %q for %+v", test.expected, actual.HomeAccountID, test)
+		}
+	}
+}
+
+func TestDeviceCodeCredentialCachedAccount(t *testing.T) {
+	cred, err := NewDeviceCodeCredential(&DeviceCodeCredentialOptions{
+		UserPrompt: func(context.Context, DeviceCodeMessage) error {
+			t.Fatal("the credential shouldn't prompt when the cache has an account")
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := public.Account{HomeAccountID: "id"}
+	cred.client = fakePublicClient{accounts: []public.Account{account}, silentAuth: true}
+	if _, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{liveTestScope}}); err != nil {
+		t.Fatal(err)
+	}
+	if cred.account.HomeAccountID != account.HomeAccountID {
+		t.Fatalf("expected the cached account, got %+v", cred.account)
+	}
+}
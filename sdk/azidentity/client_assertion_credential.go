@@ -33,6 +33,9 @@ type ClientAssertionCredential struct {
 type ClientAssertionCredentialOptions struct {
 	azcore.ClientOptions
 
+	// Cache persists tokens between processes. By default, the credential caches tokens only in memory.
+	Cache Cache
+
 	// DisableInstanceDiscovery allows disconnected cloud solutions to skip instance discovery for unknown authority hosts.
 	DisableInstanceDiscovery bool
 }
@@ -50,7 +53,7 @@ func NewClientAssertionCredential(tenantID, clientID string, getAssertion func(c
 			return getAssertion(ctx)
 		},
 	)
-	c, err := getConfidentialClient(clientID, tenantID, cred, &options.ClientOptions, confidential.WithInstanceDiscovery(!options.DisableInstanceDiscovery))
+	c, err := getConfidentialClient(clientID, tenantID, cred, &options.ClientOptions, confidential.WithInstanceDiscovery(!options.DisableInstanceDiscovery), confidential.WithAccessor(newCacheAccessor(options.Cache)))
 	if err != nil {
 		return nil, err
 	}
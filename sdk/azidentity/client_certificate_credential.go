@@ -25,6 +25,9 @@ const credNameCert = "ClientCertificateCredential"
 type ClientCertificateCredentialOptions struct {
 	azcore.ClientOptions
 
+	// Cache persists tokens between processes. By default, the credential caches tokens only in memory.
+	Cache Cache
+
 	// DisableInstanceDiscovery allows disconnected cloud solutions to skip instance discovery for unknown authority hosts.
 	DisableInstanceDiscovery bool
 
@@ -55,7 +58,7 @@ func NewClientCertificateCredential(tenantID string, clientID string, certs []*x
 	if options.SendCertificateChain {
 		o = append(o, confidential.WithX5C())
 	}
-	o = append(o, confidential.WithInstanceDiscovery(!options.DisableInstanceDiscovery))
+	o = append(o, confidential.WithInstanceDiscovery(!options.DisableInstanceDiscovery), confidential.WithAccessor(newCacheAccessor(options.Cache)))
 	c, err := getConfidentialClient(clientID, tenantID, cred, &options.ClientOptions, o...)
 	if err != nil {
 		return nil, err
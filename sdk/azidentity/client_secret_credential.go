@@ -21,6 +21,9 @@ const credNameSecret = "ClientSecretCredential"
 type ClientSecretCredentialOptions struct {
 	azcore.ClientOptions
 
+	// Cache persists tokens between processes. By default, the credential caches tokens only in memory.
+	Cache Cache
+
 	// DisableInstanceDiscovery allows disconnected cloud solutions to skip instance discovery for unknown authority hosts.
 	DisableInstanceDiscovery bool
 }
@@ -39,7 +42,7 @@ func NewClientSecretCredential(tenantID string, clientID string, clientSecret st
 	if err != nil {
 		return nil, err
 	}
-	c, err := getConfidentialClient(clientID, tenantID, cred, &options.ClientOptions, confidential.WithInstanceDiscovery(!options.DisableInstanceDiscovery))
+	c, err := getConfidentialClient(clientID, tenantID, cred, &options.ClientOptions, confidential.WithInstanceDiscovery(!options.DisableInstanceDiscovery), confidential.WithAccessor(newCacheAccessor(options.Cache)))
 	if err != nil {
 		return nil, err
 	}
@@ -26,8 +26,8 @@ type DefaultAzureCredentialOptions struct {
 	// DisableInstanceDiscovery allows disconnected cloud solutions to skip instance discovery for unknown authority hosts.
 	DisableInstanceDiscovery bool
 
-	// TenantID identifies the tenant the Azure CLI should authenticate in.
-	// Defaults to the CLI's default tenant, which is typically the home tenant of the user logged in to the CLI.
+	// TenantID identifies the tenant the Azure CLI, Azure Developer CLI and Azure PowerShell should authenticate in.
+	// Defaults to each tool's default tenant, which is typically the home tenant of the logged in user.
 	TenantID string
 }
 
@@ -42,6 +42,8 @@ type DefaultAzureCredentialOptions struct {
 //     more control over its configuration.
 //   - [ManagedIdentityCredential]
 //   - [AzureCLICredential]
+//   - [AzureDeveloperCLICredential]
+//   - [AzurePowerShellCredential]
 //
 // Consult the documentation for these credential types for more information on how they authenticate.
 // Once a credential has successfully authenticated, DefaultAzureCredential will use that credential for
@@ -113,6 +115,22 @@ func NewDefaultAzureCredential(options *DefaultAzureCredentialOptions) (*Default
 		creds = append(creds, &defaultCredentialErrorReporter{credType: credNameAzureCLI, err: err})
 	}
 
+	azdCred, err := NewAzureDeveloperCLICredential(&AzureDeveloperCLICredentialOptions{TenantID: options.TenantID})
+	if err == nil {
+		creds = append(creds, azdCred)
+	} else {
+		errorMessages = append(errorMessages, credNameAzureDeveloperCLI+": "+err.Error())
+		creds = append(creds, &defaultCredentialErrorReporter{credType: credNameAzureDeveloperCLI, err: err})
+	}
+
+	psCred, err := NewAzurePowerShellCredential(&AzurePowerShellCredentialOptions{TenantID: options.TenantID})
+	if err == nil {
+		creds = append(creds, psCred)
+	} else {
+		errorMessages = append(errorMessages, credNameAzurePowerShell+": "+err.Error())
+		creds = append(creds, &defaultCredentialErrorReporter{credType: credNameAzurePowerShell, err: err})
+	}
+
 	err = defaultAzureCredentialConstructorErrorHandler(len(creds), errorMessages)
 	if err != nil {
 		return nil, err
@@ -22,10 +22,24 @@ const credNameDeviceCode = "DeviceCodeCredential"
 type DeviceCodeCredentialOptions struct {
 	azcore.ClientOptions
 
+	// AuthenticationRecord returned by a call to a credential's Authenticate method. Set this option
+	// to enable the credential to use data from a previous authentication, such as the user's cached
+	// tokens in a persistent Cache.
+	AuthenticationRecord AuthenticationRecord
+
+	// Cache persists tokens between processes, so the credential can authenticate users silently with tokens
+	// cached by earlier processes. By default, the credential caches tokens only in memory.
+	Cache Cache
+
 	// ClientID is the ID of the application users will authenticate to.
 	// Defaults to the ID of an Azure development application.
 	ClientID string
 
+	// DisableAutomaticAuthentication prevents the credential from automatically prompting the user to authenticate.
+	// When this option is true, GetToken returns ErrAuthenticationRequired when user interaction is necessary
+	// to acquire a token.
+	DisableAutomaticAuthentication bool
+
 	// DisableInstanceDiscovery allows disconnected cloud solutions to skip instance discovery for unknown authority hosts.
 	DisableInstanceDiscovery bool
 
@@ -42,10 +56,16 @@ type DeviceCodeCredentialOptions struct {
 
 func (o *DeviceCodeCredentialOptions) init() {
 	if o.TenantID == "" {
-		o.TenantID = organizationsTenantID
+		o.TenantID = o.AuthenticationRecord.TenantID
+		if o.TenantID == "" {
+			o.TenantID = organizationsTenantID
+		}
 	}
 	if o.ClientID == "" {
-		o.ClientID = developerSignOnClientID
+		o.ClientID = o.AuthenticationRecord.ClientID
+		if o.ClientID == "" {
+			o.ClientID = developerSignOnClientID
+		}
 	}
 	if o.UserPrompt == nil {
 		o.UserPrompt = func(ctx context.Context, dc DeviceCodeMessage) error {
@@ -74,6 +94,7 @@ type DeviceCodeCredential struct {
 	client     publicClient
 	userPrompt func(context.Context, DeviceCodeMessage) error
 	account    public.Account
+	options    DeviceCodeCredentialOptions
 }
 
 // NewDeviceCodeCredential creates a DeviceCodeCredential. Pass nil to accept default options.
@@ -83,11 +104,21 @@ func NewDeviceCodeCredential(options *DeviceCodeCredentialOptions) (*DeviceCodeC
 		cp = *options
 	}
 	cp.init()
-	c, err := getPublicClient(cp.ClientID, cp.TenantID, &cp.ClientOptions, public.WithInstanceDiscovery(!cp.DisableInstanceDiscovery))
+	c, err := getPublicClient(cp.ClientID, cp.TenantID, &cp.ClientOptions, public.WithInstanceDiscovery(!cp.DisableInstanceDiscovery), public.WithCache(newCacheAccessor(cp.Cache)))
 	if err != nil {
 		return nil, err
 	}
-	return &DeviceCodeCredential{userPrompt: cp.UserPrompt, client: c}, nil
+	return &DeviceCodeCredential{userPrompt: cp.UserPrompt, client: c, account: cp.AuthenticationRecord.account(), options: cp}, nil
+}
+
+// Authenticate a user via the device code flow. Subsequent calls to GetToken will automatically use the returned
+// AuthenticationRecord. Pass nil for opts to request a token for Azure Resource Manager.
+func (c *DeviceCodeCredential) Authenticate(ctx context.Context, opts *policy.TokenRequestOptions) (AuthenticationRecord, error) {
+	tro := authenticateScopes(opts, c.options.ClientOptions)
+	if _, err := c.requestToken(ctx, tro); err != nil {
+		return AuthenticationRecord{}, err
+	}
+	return newAuthenticationRecord(c.account, c.options.ClientID), nil
 }
 
 // GetToken requests an access token from Azure Active Directory. It will begin the device code flow and poll until the user completes authentication.
@@ -96,10 +127,21 @@ func (c *DeviceCodeCredential) GetToken(ctx context.Context, opts policy.TokenRe
 	if len(opts.Scopes) == 0 {
 		return azcore.AccessToken{}, errors.New(credNameDeviceCode + ": GetToken() requires at least one scope")
 	}
+	if c.account.HomeAccountID == "" {
+		c.account = cachedAccount(c.client, "")
+	}
 	ar, err := c.client.AcquireTokenSilent(ctx, opts.Scopes, public.WithSilentAccount(c.account))
 	if err == nil {
 		return azcore.AccessToken{Token: ar.AccessToken, ExpiresOn: ar.ExpiresOn.UTC()}, err
 	}
+	if c.options.DisableAutomaticAuthentication {
+		return azcore.AccessToken{}, ErrAuthenticationRequired
+	}
+	return c.requestToken(ctx, opts)
+}
+
+// requestToken authenticates the user via the device code flow
+func (c *DeviceCodeCredential) requestToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
 	dc, err := c.client.AcquireTokenByDeviceCode(ctx, opts.Scopes)
 	if err != nil {
 		return azcore.AccessToken{}, newAuthenticationFailedErrorFromMSALError(credNameDeviceCode, err)
@@ -112,7 +154,7 @@ func (c *DeviceCodeCredential) GetToken(ctx context.Context, opts policy.TokenRe
 	if err != nil {
 		return azcore.AccessToken{}, err
 	}
-	ar, err = dc.AuthenticationResult(ctx)
+	ar, err := dc.AuthenticationResult(ctx)
 	if err != nil {
 		return azcore.AccessToken{}, newAuthenticationFailedErrorFromMSALError(credNameDeviceCode, err)
 	}
@@ -79,6 +79,10 @@ func (e *AuthenticationFailedError) Error() string {
 	switch e.credType {
 	case credNameAzureCLI:
 		anchor = "azure-cli"
+	case credNameAzureDeveloperCLI:
+		anchor = "azd"
+	case credNameAzurePowerShell:
+		anchor = "azure-pwsh"
 	case credNameCert:
 		anchor = "client-cert"
 	case credNameSecret:
@@ -129,3 +133,7 @@ func (e *credentialUnavailableError) Error() string {
 func (e *credentialUnavailableError) NonRetriable() {}
 
 var _ errorinfo.NonRetriable = (*credentialUnavailableError)(nil)
+
+// ErrAuthenticationRequired indicates a credential's Authenticate method must be called to acquire a token
+// because user interaction is required and the credential is configured not to automatically prompt the user.
+var ErrAuthenticationRequired error = &credentialUnavailableError{"can't acquire a token without user interaction. Call Authenticate to authenticate a user interactively"}
@@ -21,10 +21,24 @@ const credNameBrowser = "InteractiveBrowserCredentiall"
 type InteractiveBrowserCredentialOptions struct {
 	azcore.ClientOptions
 
+	// AuthenticationRecord returned by a call to a credential's Authenticate method. Set this option
+	// to enable the credential to use data from a previous authentication, such as the user's cached
+	// tokens in a persistent Cache.
+	AuthenticationRecord AuthenticationRecord
+
+	// Cache persists tokens between processes, so the credential can authenticate users silently with tokens
+	// cached by earlier processes. By default, the credential caches tokens only in memory.
+	Cache Cache
+
 	// ClientID is the ID of the application users will authenticate to.
 	// Defaults to the ID of an Azure development application.
 	ClientID string
 
+	// DisableAutomaticAuthentication prevents the credential from automatically prompting the user to authenticate.
+	// When this option is true, GetToken returns ErrAuthenticationRequired when user interaction is necessary
+	// to acquire a token.
+	DisableAutomaticAuthentication bool
+
 	// DisableInstanceDiscovery allows disconnected cloud solutions to skip instance discovery for unknown authority hosts.
 	DisableInstanceDiscovery bool
 
@@ -42,10 +56,16 @@ type InteractiveBrowserCredentialOptions struct {
 
 func (o *InteractiveBrowserCredentialOptions) init() {
 	if o.TenantID == "" {
-		o.TenantID = organizationsTenantID
+		o.TenantID = o.AuthenticationRecord.TenantID
+		if o.TenantID == "" {
+			o.TenantID = organizationsTenantID
+		}
 	}
 	if o.ClientID == "" {
-		o.ClientID = developerSignOnClientID
+		o.ClientID = o.AuthenticationRecord.ClientID
+		if o.ClientID == "" {
+			o.ClientID = developerSignOnClientID
+		}
 	}
 }
 
@@ -63,11 +83,21 @@ func NewInteractiveBrowserCredential(options *InteractiveBrowserCredentialOption
 		cp = *options
 	}
 	cp.init()
-	c, err := getPublicClient(cp.ClientID, cp.TenantID, &cp.ClientOptions, public.WithInstanceDiscovery(!cp.DisableInstanceDiscovery))
+	c, err := getPublicClient(cp.ClientID, cp.TenantID, &cp.ClientOptions, public.WithInstanceDiscovery(!cp.DisableInstanceDiscovery), public.WithCache(newCacheAccessor(cp.Cache)))
 	if err != nil {
 		return nil, err
 	}
-	return &InteractiveBrowserCredential{options: cp, client: c}, nil
+	return &InteractiveBrowserCredential{options: cp, client: c, account: cp.AuthenticationRecord.account()}, nil
+}
+
+// Authenticate a user via the default browser. Subsequent calls to GetToken will automatically use the returned
+// AuthenticationRecord. Pass nil for opts to request a token for Azure Resource Manager.
+func (c *InteractiveBrowserCredential) Authenticate(ctx context.Context, opts *policy.TokenRequestOptions) (AuthenticationRecord, error) {
+	tro := authenticateScopes(opts, c.options.ClientOptions)
+	if _, err := c.requestToken(ctx, tro); err != nil {
+		return AuthenticationRecord{}, err
+	}
+	return newAuthenticationRecord(c.account, c.options.ClientID), nil
 }
 
 // GetToken requests an access token from Azure Active Directory. This method is called automatically by Azure SDK clients.
@@ -75,13 +105,23 @@ func (c *InteractiveBrowserCredential) GetToken(ctx context.Context, opts policy
 	if len(opts.Scopes) == 0 {
 		return azcore.AccessToken{}, errors.New(credNameBrowser + ": GetToken() requires at least one scope")
 	}
+	if c.account.HomeAccountID == "" {
+		c.account = cachedAccount(c.client, c.options.LoginHint)
+	}
 	ar, err := c.client.AcquireTokenSilent(ctx, opts.Scopes, public.WithSilentAccount(c.account))
 	if err == nil {
 		logGetTokenSuccess(c, opts)
 		return azcore.AccessToken{Token: ar.AccessToken, ExpiresOn: ar.ExpiresOn.UTC()}, err
 	}
+	if c.options.DisableAutomaticAuthentication {
+		return azcore.AccessToken{}, ErrAuthenticationRequired
+	}
+	return c.requestToken(ctx, opts)
+}
 
-	ar, err = c.client.AcquireTokenInteractive(ctx, opts.Scopes, public.WithLoginHint(c.options.LoginHint), public.WithRedirectURI(c.options.RedirectURL))
+// requestToken authenticates the user interactively via the default browser
+func (c *InteractiveBrowserCredential) requestToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	ar, err := c.client.AcquireTokenInteractive(ctx, opts.Scopes, public.WithLoginHint(c.options.LoginHint), public.WithRedirectURI(c.options.RedirectURL))
 	if err != nil {
 		return azcore.AccessToken{}, newAuthenticationFailedErrorFromMSALError(credNameBrowser, err)
 	}
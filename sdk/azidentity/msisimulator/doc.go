@@ -0,0 +1,26 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package msisimulator simulates the managed identity endpoints of Azure hosting environments, so that applications
+// and tests can run azidentity.ManagedIdentityCredential off Azure. A [Server] implements the protocol of one
+// environment: App Service, Azure Arc, Cloud Shell, IMDS or Service Fabric. It issues JWT access tokens signed by
+// a local key, which Azure services won't accept.
+//
+// Configure ManagedIdentityCredential to use a Server by setting the environment variables returned by [Server.Env]
+// or, for IMDS and Service Fabric, by setting the credential's ClientOptions.Transport to the Server:
+//
+//	srv, err := msisimulator.NewServer(&msisimulator.Options{
+//		Environment:    msisimulator.IMDS,
+//		SystemAssigned: &msisimulator.Identity{},
+//	})
+//	if err != nil {
+//		// TODO: handle error
+//	}
+//	defer srv.Close()
+//	cred, err := azidentity.NewManagedIdentityCredential(&azidentity.ManagedIdentityCredentialOptions{
+//		ClientOptions: azcore.ClientOptions{Transport: srv},
+//	})
+package msisimulator
@@ -0,0 +1,624 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package msisimulator
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/uuid"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Environment is a hosting environment whose managed identity endpoint a Server simulates.
+type Environment string
+
+const (
+	// AppService simulates Azure App Service and Azure Functions.
+	AppService Environment = "AppService"
+	// AzureArc simulates the Azure Arc hybrid instance metadata service, which challenges token
+	// requests to prove the client can read a secret file.
+	AzureArc Environment = "AzureArc"
+	// CloudShell simulates Azure Cloud Shell.
+	CloudShell Environment = "CloudShell"
+	// IMDS simulates the Azure instance metadata service of virtual machines.
+	IMDS Environment = "IMDS"
+	// ServiceFabric simulates Service Fabric, whose endpoint serves HTTPS with a self-signed certificate.
+	ServiceFabric Environment = "ServiceFabric"
+)
+
+const (
+	defaultTokenLifetime = 24 * time.Hour
+	headerMetadata       = "Metadata"
+
+	appServiceAPIVersion    = "2019-08-01"
+	appServicePath          = "/msi/token"
+	azureArcAPIVersion      = "2019-08-15"
+	cloudShellPath          = "/oauth2/token"
+	imdsPath                = "/metadata/identity/oauth2/token"
+	serviceFabricAPIVersion = "2019-07-01-preview"
+)
+
+// Identity is a managed identity. A Server generates values for empty ClientID and ObjectID fields.
+type Identity struct {
+	// ClientID is the identity's client ID, which tokens contain in the appid claim.
+	ClientID string
+
+	// ObjectID is the identity's object ID, which tokens contain in the oid and sub claims.
+	ObjectID string
+
+	// ResourceID is the identity's Azure resource ID, which tokens contain in the xms_mirid claim.
+	ResourceID string
+}
+
+// Options contains optional parameters for NewServer.
+type Options struct {
+	// ArcKeyDirectory is the directory in which an AzureArc server writes the secret files its challenges
+	// refer to. Defaults to a temporary directory the server removes when closed.
+	ArcKeyDirectory string
+
+	// Environment is the hosting environment to simulate. Defaults to IMDS.
+	Environment Environment
+
+	// SigningKey signs the server's tokens. Defaults to a new key.
+	SigningKey *rsa.PrivateKey
+
+	// SystemAssigned is the simulated resource's system-assigned identity. When nil, the resource has no
+	// system-assigned identity, so requests that don't specify a user-assigned identity fail as they would in Azure.
+	SystemAssigned *Identity
+
+	// TenantID is the tenant of the server's identities. Defaults to a random ID.
+	TenantID string
+
+	// TokenLifetime is how long the server's tokens are valid. Defaults to 24 hours.
+	TokenLifetime time.Duration
+
+	// UserAssigned are the user-assigned identities of the simulated resource. Azure Arc and
+	// Cloud Shell don't support user-assigned identities.
+	UserAssigned []Identity
+}
+
+// Server simulates a managed identity endpoint. It listens on a loopback address and
+// also implements http.Handler and azcore's policy.Transporter for in-process use.
+// Don't use this type directly, use NewServer() instead.
+type Server struct {
+	arcDir       string
+	removeArcDir bool
+	cert         *x509.Certificate
+	env          Environment
+	key          *rsa.PrivateKey
+	lifetime     time.Duration
+	secret       string
+	srv          *http.Server
+	system       *Identity
+	tenantID     string
+	url          string
+	user         []Identity
+
+	// mu synchronizes access to arcSecrets, which contains the secrets of issued Azure Arc challenges
+	mu         sync.Mutex
+	arcSecrets map[string]bool
+}
+
+// NewServer creates and starts a Server. Pass nil to accept default options, which simulate IMDS for
+// a resource having no managed identity. Call Close when the Server is no longer needed.
+func NewServer(options *Options) (*Server, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	s := &Server{
+		arcDir:     options.ArcKeyDirectory,
+		arcSecrets: map[string]bool{},
+		env:        options.Environment,
+		key:        options.SigningKey,
+		lifetime:   options.TokenLifetime,
+		tenantID:   options.TenantID,
+	}
+	var err error
+	switch s.env {
+	case "":
+		s.env = IMDS
+	case AppService, AzureArc, CloudShell, IMDS, ServiceFabric:
+	default:
+		return nil, fmt.Errorf("unknown environment %q", s.env)
+	}
+	if s.lifetime <= 0 {
+		s.lifetime = defaultTokenLifetime
+	}
+	if s.key == nil {
+		if s.key, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			return nil, err
+		}
+	}
+	if s.tenantID == "" {
+		if s.tenantID, err = newID(); err != nil {
+			return nil, err
+		}
+	}
+	if options.SystemAssigned != nil {
+		id, err := completeIdentity(*options.SystemAssigned)
+		if err != nil {
+			return nil, err
+		}
+		s.system = &id
+	}
+	for _, id := range options.UserAssigned {
+		if id, err = completeIdentity(id); err != nil {
+			return nil, err
+		}
+		s.user = append(s.user, id)
+	}
+	if s.secret, err = newSecret(); err != nil {
+		return nil, err
+	}
+	if s.env == AzureArc && s.arcDir == "" {
+		if s.arcDir, err = os.MkdirTemp("", "msisimulator"); err != nil {
+			return nil, err
+		}
+		s.removeArcDir = true
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		s.cleanup()
+		return nil, err
+	}
+	s.srv = &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	s.url = "http://" + l.Addr().String()
+	if s.env == ServiceFabric {
+		cert, err := newCertificate()
+		if err != nil {
+			l.Close()
+			s.cleanup()
+			return nil, err
+		}
+		s.cert = cert.Leaf
+		l = tls.NewListener(l, &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+		s.url = "https://" + l.Addr().String()
+	}
+	go func() { _ = s.srv.Serve(l) }()
+	return s, nil
+}
+
+// Close stops the server and removes any files it created.
+func (s *Server) Close() error {
+	err := s.srv.Close()
+	s.cleanup()
+	return err
+}
+
+func (s *Server) cleanup() {
+	if s.removeArcDir {
+		_ = os.RemoveAll(s.arcDir)
+	}
+}
+
+// Client returns an HTTP client configured to trust the server's certificate. This is necessary only
+// for Service Fabric. *http.Client implements azcore's policy.Transporter, so a credential can use it
+// as its ClientOptions.Transport.
+func (s *Server) Client() *http.Client {
+	if s.cert == nil {
+		return &http.Client{}
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(s.cert)
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}}}
+}
+
+// Do handles req in process, regardless of its URL. This enables a credential whose ClientOptions.Transport
+// is the server to authenticate with it without a network connection, including to IMDS's link-local address.
+func (s *Server) Do(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// Env returns the environment variables by which ManagedIdentityCredential detects the simulated
+// environment. IMDS has no such variables because its endpoint is at a fixed address, so to use an
+// IMDS server, set the credential's ClientOptions.Transport to the server.
+func (s *Server) Env() map[string]string {
+	switch s.env {
+	case AppService:
+		return map[string]string{"IDENTITY_ENDPOINT": s.url + appServicePath, "IDENTITY_HEADER": s.secret}
+	case AzureArc:
+		return map[string]string{"IDENTITY_ENDPOINT": s.url + imdsPath, "IMDS_ENDPOINT": s.url}
+	case CloudShell:
+		return map[string]string{"MSI_ENDPOINT": s.url + cloudShellPath}
+	case ServiceFabric:
+		thumbprint := sha1.Sum(s.cert.Raw)
+		return map[string]string{
+			"IDENTITY_ENDPOINT":          s.url + imdsPath,
+			"IDENTITY_HEADER":            s.secret,
+			"IDENTITY_SERVER_THUMBPRINT": strings.ToUpper(hex.EncodeToString(thumbprint[:])),
+		}
+	default:
+		return map[string]string{}
+	}
+}
+
+// Issuer returns the issuer of the server's tokens.
+func (s *Server) Issuer() string {
+	return fmt.Sprintf("https://sts.windows.net/%s/", s.tenantID)
+}
+
+// PublicKey returns the key that verifies the signatures of the server's tokens.
+func (s *Server) PublicKey() *rsa.PublicKey {
+	return &s.key.PublicKey
+}
+
+// URL returns the base URL of the server's loopback listener.
+func (s *Server) URL() string {
+	return s.url
+}
+
+// ServeHTTP implements http.Handler, responding to token requests as the simulated environment would.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch s.env {
+	case AppService:
+		s.serveAppService(w, req)
+	case AzureArc:
+		s.serveAzureArc(w, req)
+	case CloudShell:
+		s.serveCloudShell(w, req)
+	case IMDS:
+		s.serveIMDS(w, req)
+	case ServiceFabric:
+		s.serveServiceFabric(w, req)
+	}
+}
+
+func (s *Server) serveAppService(w http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	if !s.checkRequest(w, req, http.MethodGet, appServicePath, appServiceAPIVersion) {
+		return
+	}
+	if req.Header.Get("X-IDENTITY-HEADER") != s.secret {
+		writeError(w, http.StatusUnauthorized, "unauthorized_client", "X-IDENTITY-HEADER doesn't match IDENTITY_HEADER")
+		return
+	}
+	id, ok := s.identity(w, q)
+	if !ok {
+		return
+	}
+	tk, exp, ok := s.token(w, id, q.Get("resource"))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": tk,
+		"client_id":    id.ClientID,
+		"expires_on":   strconv.FormatInt(exp.Unix(), 10),
+		"resource":     q.Get("resource"),
+		"token_type":   "Bearer",
+	})
+}
+
+func (s *Server) serveAzureArc(w http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	if !s.checkRequest(w, req, http.MethodGet, imdsPath, azureArcAPIVersion) {
+		return
+	}
+	auth := req.Header.Get("Authorization")
+	if auth == "" {
+		// challenge the client to prove it can read a file only privileged local users can read
+		secret, err := newSecret()
+		if err == nil {
+			path := filepath.Join(s.arcDir, secret+".key")
+			if err = os.WriteFile(path, []byte(secret), 0600); err == nil {
+				s.mu.Lock()
+				s.arcSecrets[secret] = true
+				s.mu.Unlock()
+				w.Header().Set("WWW-Authenticate", "Basic realm="+path)
+				writeError(w, http.StatusUnauthorized, "unauthorized_client", "authorization required")
+				return
+			}
+		}
+		writeError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+	s.mu.Lock()
+	valid := s.arcSecrets[strings.TrimPrefix(auth, "Basic ")]
+	s.mu.Unlock()
+	if !valid {
+		writeError(w, http.StatusUnauthorized, "unauthorized_client", "invalid secret")
+		return
+	}
+	if hasIdentityParameter(q) {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Azure Arc doesn't support user-assigned identities")
+		return
+	}
+	s.writeIMDSToken(w, q)
+}
+
+func (s *Server) serveCloudShell(w http.ResponseWriter, req *http.Request) {
+	if !s.checkRequest(w, req, http.MethodPost, cloudShellPath, "") {
+		return
+	}
+	if err := req.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	if hasIdentityParameter(req.Form) {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Cloud Shell doesn't support user-assigned identities")
+		return
+	}
+	s.writeIMDSToken(w, req.PostForm)
+}
+
+func (s *Server) serveIMDS(w http.ResponseWriter, req *http.Request) {
+	if !s.checkRequest(w, req, http.MethodGet, imdsPath, "") {
+		return
+	}
+	// IMDS supports several API versions
+	if req.URL.Query().Get("api-version") == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "api-version is required")
+		return
+	}
+	s.writeIMDSToken(w, req.URL.Query())
+}
+
+func (s *Server) serveServiceFabric(w http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	if !s.checkRequest(w, req, http.MethodGet, imdsPath, serviceFabricAPIVersion) {
+		return
+	}
+	if req.Header.Get("Secret") != s.secret {
+		// Service Fabric's errors have a different shape than other environments'
+		writeJSON(w, http.StatusUnauthorized, map[string]interface{}{
+			"error": map[string]string{"code": "SecretHeaderNotFound", "message": "Secret doesn't match IDENTITY_HEADER"},
+		})
+		return
+	}
+	id, ok := s.identity(w, q)
+	if !ok {
+		return
+	}
+	tk, exp, ok := s.token(w, id, q.Get("resource"))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": tk,
+		"expires_on":   exp.Unix(),
+		"resource":     q.Get("resource"),
+		"token_type":   "Bearer",
+	})
+}
+
+// checkRequest validates the method, path, API version and, where the environment requires it, Metadata header
+// of a token request. It writes an error response and returns false when the request is invalid.
+func (s *Server) checkRequest(w http.ResponseWriter, req *http.Request, method, path, apiVersion string) bool {
+	if req.URL.Path != path {
+		writeError(w, http.StatusNotFound, "not_found", "no endpoint at "+req.URL.Path)
+		return false
+	}
+	if req.Method != method {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request", req.Method+" isn't supported")
+		return false
+	}
+	switch s.env {
+	case AzureArc, CloudShell, IMDS:
+		// IMDS responds 400 to requests without this header, which clients use to probe for IMDS
+		if req.Header.Get(headerMetadata) != "true" {
+			writeError(w, http.StatusBadRequest, "invalid_request", "Required metadata header not specified")
+			return false
+		}
+	}
+	if apiVersion != "" {
+		if v := req.URL.Query().Get("api-version"); v != apiVersion {
+			writeError(w, http.StatusBadRequest, "invalid_request", fmt.Sprintf("unsupported api-version %q", v))
+			return false
+		}
+	}
+	return true
+}
+
+// writeIMDSToken writes a token response in the format of IMDS, which Azure Arc and Cloud Shell share
+func (s *Server) writeIMDSToken(w http.ResponseWriter, params url.Values) {
+	id, ok := s.identity(w, params)
+	if !ok {
+		return
+	}
+	resource := params.Get("resource")
+	tk, exp, ok := s.token(w, id, resource)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":   tk,
+		"client_id":      id.ClientID,
+		"expires_in":     strconv.Itoa(int(exp.Sub(now).Seconds())),
+		"expires_on":     strconv.FormatInt(exp.Unix(), 10),
+		"ext_expires_in": strconv.Itoa(int(exp.Sub(now).Seconds())),
+		"not_before":     strconv.FormatInt(now.Unix(), 10),
+		"resource":       resource,
+		"token_type":     "Bearer",
+	})
+}
+
+// identity returns the identity a request specifies by client, object or resource ID, or the system-assigned
+// identity when the request specifies none. It writes an error response and returns false when there's no
+// such identity.
+func (s *Server) identity(w http.ResponseWriter, params url.Values) (Identity, bool) {
+	ids := s.user
+	if s.system != nil {
+		ids = append([]Identity{*s.system}, ids...)
+	}
+	for _, p := range identityParameters {
+		v := params.Get(p.name)
+		if v == "" {
+			continue
+		}
+		for _, id := range ids {
+			if strings.EqualFold(p.value(id), v) {
+				return id, true
+			}
+		}
+		writeError(w, http.StatusBadRequest, "invalid_request", "Identity not found")
+		return Identity{}, false
+	}
+	if s.system == nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Identity not found")
+		return Identity{}, false
+	}
+	return *s.system, true
+}
+
+// token returns a token for id with the specified audience. It writes an error response and returns false
+// when it can't create the token.
+func (s *Server) token(w http.ResponseWriter, id Identity, resource string) (string, time.Time, bool) {
+	if resource == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "resource is required")
+		return "", time.Time{}, false
+	}
+	now := time.Now()
+	exp := now.Add(s.lifetime)
+	claims := jwt.MapClaims{
+		"aud":   resource,
+		"appid": id.ClientID,
+		"exp":   exp.Unix(),
+		"iat":   now.Unix(),
+		"idtyp": "app",
+		"iss":   s.Issuer(),
+		"nbf":   now.Unix(),
+		"oid":   id.ObjectID,
+		"sub":   id.ObjectID,
+		"tid":   s.tenantID,
+		"ver":   "1.0",
+	}
+	if id.ResourceID != "" {
+		claims["xms_mirid"] = id.ResourceID
+	}
+	tk, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(s.key)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return "", time.Time{}, false
+	}
+	return tk, exp, true
+}
+
+// identityParameters are the request parameters that select a user-assigned identity
+var identityParameters = []struct {
+	name  string
+	value func(Identity) string
+}{
+	{"client_id", func(id Identity) string { return id.ClientID }},
+	{"mi_res_id", func(id Identity) string { return id.ResourceID }},
+	{"msi_res_id", func(id Identity) string { return id.ResourceID }},
+	{"object_id", func(id Identity) string { return id.ObjectID }},
+}
+
+// hasIdentityParameter returns true when params select a user-assigned identity
+func hasIdentityParameter(params url.Values) bool {
+	for _, p := range identityParameters {
+		if params.Get(p.name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func writeError(w http.ResponseWriter, status int, code, description string) {
+	writeJSON(w, status, map[string]string{"error": code, "error_description": description})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		status = http.StatusInternalServerError
+		b = []byte(`{"error":"server_error"}`)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
+
+// completeIdentity returns a copy of id having generated values for its empty client and object IDs
+func completeIdentity(id Identity) (Identity, error) {
+	var err error
+	if id.ClientID == "" {
+		if id.ClientID, err = newID(); err != nil {
+			return id, err
+		}
+	}
+	if id.ObjectID == "" {
+		id.ObjectID, err = newID()
+	}
+	return id, err
+}
+
+func newID() (string, error) {
+	u, err := uuid.New()
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// newCertificate creates a self-signed certificate for the loopback address
+func newCertificate() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		BasicConstraintsValid: true,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		NotAfter:              now.Add(24 * time.Hour),
+		NotBefore:             now.Add(-time.Hour),
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "msisimulator"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, Leaf: leaf, PrivateKey: key}, nil
+}
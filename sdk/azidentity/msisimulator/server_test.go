@@ -0,0 +1,293 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package msisimulator_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity/msisimulator"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	resource = "https://management.azure.com"
+	scope    = resource + "/.default"
+)
+
+// clearEnv unsets the environment variables ManagedIdentityCredential uses to detect its hosting environment
+func clearEnv(t *testing.T) {
+	for _, k := range []string{"IDENTITY_ENDPOINT", "IDENTITY_HEADER", "IDENTITY_SERVER_THUMBPRINT", "IMDS_ENDPOINT", "MSI_ENDPOINT"} {
+		if v, ok := os.LookupEnv(k); ok {
+			t.Setenv(k, v)
+			os.Unsetenv(k)
+		}
+	}
+}
+
+func newServer(t *testing.T, o *msisimulator.Options) *msisimulator.Server {
+	clearEnv(t)
+	srv, err := msisimulator.NewServer(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { srv.Close() })
+	for k, v := range srv.Env() {
+		t.Setenv(k, v)
+	}
+	return srv
+}
+
+// verify parses a token the server issued and returns its claims
+func verify(t *testing.T, srv *msisimulator.Server, tk azcore.AccessToken) jwt.MapClaims {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tk.Token, claims, func(*jwt.Token) (interface{}, error) { return srv.PublicKey(), nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !claims.VerifyAudience(resource, true) {
+		t.Fatalf("unexpected audience %v", claims["aud"])
+	}
+	if !claims.VerifyIssuer(srv.Issuer(), true) {
+		t.Fatalf("unexpected issuer %v", claims["iss"])
+	}
+	if d := time.Until(tk.ExpiresOn); d < 23*time.Hour || d > 25*time.Hour {
+		t.Fatalf("unexpected expiration %v", tk.ExpiresOn)
+	}
+	return claims
+}
+
+func TestEnvironments(t *testing.T) {
+	system := msisimulator.Identity{ClientID: "system-client-id", ObjectID: "system-object-id"}
+	for _, env := range []msisimulator.Environment{msisimulator.AppService, msisimulator.AzureArc, msisimulator.CloudShell, msisimulator.IMDS, msisimulator.ServiceFabric} {
+		t.Run(string(env), func(t *testing.T) {
+			srv := newServer(t, &msisimulator.Options{Environment: env, SystemAssigned: &system})
+			o := azidentity.ManagedIdentityCredentialOptions{}
+			switch env {
+			case msisimulator.IMDS:
+				// the IMDS endpoint has a fixed address, so the server must handle requests in process
+				o.Transport = srv
+			case msisimulator.ServiceFabric:
+				o.Transport = srv.Client()
+			}
+			cred, err := azidentity.NewManagedIdentityCredential(&o)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tk, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{scope}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			claims := verify(t, srv, tk)
+			if claims["appid"] != system.ClientID || claims["oid"] != system.ObjectID {
+				t.Fatalf("unexpected identity claims %v", claims)
+			}
+		})
+	}
+}
+
+func TestUserAssigned(t *testing.T) {
+	user := msisimulator.Identity{ClientID: "user-client-id", ResourceID: "/subscriptions/sub/resourcegroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id"}
+	for _, env := range []msisimulator.Environment{msisimulator.AppService, msisimulator.IMDS} {
+		for _, id := range []azidentity.ManagedIDKind{azidentity.ClientID(user.ClientID), azidentity.ResourceID(user.ResourceID)} {
+			t.Run(string(env), func(t *testing.T) {
+				srv := newServer(t, &msisimulator.Options{Environment: env, UserAssigned: []msisimulator.Identity{user}})
+				cred, err := azidentity.NewManagedIdentityCredential(&azidentity.ManagedIdentityCredentialOptions{
+					ClientOptions: azcore.ClientOptions{Transport: srv},
+					ID:            id,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				tk, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{scope}})
+				if err != nil {
+					t.Fatal(err)
+				}
+				claims := verify(t, srv, tk)
+				if claims["appid"] != user.ClientID || claims["xms_mirid"] != user.ResourceID {
+					t.Fatalf("unexpected identity claims %v", claims)
+				}
+				if claims["oid"] == "" {
+					t.Fatal("the server should generate an object ID")
+				}
+			})
+		}
+	}
+}
+
+func TestNoIdentity(t *testing.T) {
+	srv := newServer(t, nil)
+	cred, err := azidentity.NewManagedIdentityCredential(&azidentity.ManagedIdentityCredentialOptions{
+		ClientOptions: azcore.ClientOptions{Transport: srv},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{scope}})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	var afe *azidentity.AuthenticationFailedError
+	if errors.As(err, &afe) {
+		t.Fatal("IMDS's response should indicate managed identity is unavailable, not that authentication failed")
+	}
+
+	cred, err = azidentity.NewManagedIdentityCredential(&azidentity.ManagedIdentityCredentialOptions{
+		ClientOptions: azcore.ClientOptions{Transport: srv},
+		ID:            azidentity.ClientID("unknown"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{scope}})
+	if !errors.As(err, &afe) || afe.RawResponse.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected an AuthenticationFailedError for a 400 response, got %v", err)
+	}
+}
+
+func TestProtocolErrors(t *testing.T) {
+	for _, test := range []struct {
+		env    msisimulator.Environment
+		desc   string
+		status int
+		modify func(*http.Request)
+	}{
+		{
+			env: msisimulator.IMDS, desc: "probe without Metadata header", status: http.StatusBadRequest,
+			modify: func(req *http.Request) { req.Header.Del("Metadata") },
+		},
+		{
+			env: msisimulator.IMDS, desc: "wrong path", status: http.StatusNotFound,
+			modify: func(req *http.Request) { req.URL.Path = "/metadata/instance" },
+		},
+		{
+			env: msisimulator.AppService, desc: "wrong identity header", status: http.StatusUnauthorized,
+			modify: func(req *http.Request) { req.Header.Set("X-IDENTITY-HEADER", "wrong") },
+		},
+		{
+			env: msisimulator.AzureArc, desc: "wrong secret", status: http.StatusUnauthorized,
+			modify: func(req *http.Request) { req.Header.Set("Authorization", "Basic wrong") },
+		},
+		{
+			env: msisimulator.ServiceFabric, desc: "wrong secret", status: http.StatusUnauthorized,
+			modify: func(req *http.Request) { req.Header.Set("Secret", "wrong") },
+		},
+		{
+			env: msisimulator.ServiceFabric, desc: "wrong API version", status: http.StatusBadRequest,
+			modify: func(req *http.Request) { req.URL.RawQuery = "api-version=2019-08-01&resource=" + resource },
+		},
+	} {
+		t.Run(string(test.env)+" "+test.desc, func(t *testing.T) {
+			srv := newServer(t, &msisimulator.Options{Environment: test.env, SystemAssigned: &msisimulator.Identity{}})
+			req := validRequest(t, srv, test.env)
+			test.modify(req)
+			resp, err := srv.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != test.status {
+				t.Fatalf("expected %d, got %d", test.status, resp.StatusCode)
+			}
+			// verify the unmodified request succeeds, so the failure above is due to the modification
+			if resp, err = srv.Do(validRequest(t, srv, test.env)); err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected 200, got %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+// validRequest returns a token request the simulated environment accepts
+func validRequest(t *testing.T, srv *msisimulator.Server, env msisimulator.Environment) *http.Request {
+	endpoint := srv.Env()["IDENTITY_ENDPOINT"]
+	if env == msisimulator.IMDS {
+		endpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+	}
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch env {
+	case msisimulator.AppService:
+		req.URL.RawQuery = "api-version=2019-08-01&resource=" + resource
+		req.Header.Set("X-IDENTITY-HEADER", srv.Env()["IDENTITY_HEADER"])
+	case msisimulator.AzureArc:
+		req.URL.RawQuery = "api-version=2019-08-15&resource=" + resource
+		req.Header.Set("Metadata", "true")
+		resp, err := srv.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		challenge := resp.Header.Get("WWW-Authenticate")
+		if resp.StatusCode != http.StatusUnauthorized || !strings.HasPrefix(challenge, "Basic realm=") {
+			t.Fatalf("expected a challenge, got %d %q", resp.StatusCode, challenge)
+		}
+		secret, err := os.ReadFile(strings.TrimPrefix(challenge, "Basic realm="))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Basic "+string(secret))
+	case msisimulator.IMDS:
+		req.URL.RawQuery = "api-version=2018-02-01&resource=" + resource
+		req.Header.Set("Metadata", "true")
+	case msisimulator.ServiceFabric:
+		req.URL.RawQuery = "api-version=2019-07-01-preview&resource=" + resource
+		req.Header.Set("Secret", srv.Env()["IDENTITY_HEADER"])
+	}
+	return req
+}
+
+func TestAzureArcUserAssigned(t *testing.T) {
+	newServer(t, &msisimulator.Options{Environment: msisimulator.AzureArc, UserAssigned: []msisimulator.Identity{{ClientID: "id"}}})
+	cred, err := azidentity.NewManagedIdentityCredential(&azidentity.ManagedIdentityCredentialOptions{ID: azidentity.ClientID("id")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{scope}}); err == nil {
+		t.Fatal("Azure Arc doesn't support user-assigned identities")
+	}
+}
+
+func TestArcKeyDirectoryRemoved(t *testing.T) {
+	srv, err := msisimulator.NewServer(&msisimulator.Options{Environment: msisimulator.AzureArc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(http.MethodGet, srv.Env()["IDENTITY_ENDPOINT"]+"?api-version=2019-08-15&resource="+resource, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Metadata", "true")
+	resp, err := srv.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := strings.TrimPrefix(resp.Header.Get("WWW-Authenticate"), "Basic realm=")
+	if _, err = os.Stat(path); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+	if _, err = os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the key file to be removed, got %v", err)
+	}
+}
+
+func TestUnknownEnvironment(t *testing.T) {
+	if _, err := msisimulator.NewServer(&msisimulator.Options{Environment: "Mainframe"}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
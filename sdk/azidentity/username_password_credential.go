@@ -21,6 +21,10 @@ const credNameUserPassword = "UsernamePasswordCredential"
 type UsernamePasswordCredentialOptions struct {
 	azcore.ClientOptions
 
+	// Cache persists tokens between processes, so the credential can authenticate users silently with tokens
+	// cached by earlier processes. By default, the credential caches tokens only in memory.
+	Cache Cache
+
 	// DisableInstanceDiscovery allows disconnected cloud solutions to skip instance discovery for unknown authority hosts.
 	DisableInstanceDiscovery bool
 }
@@ -42,7 +46,7 @@ func NewUsernamePasswordCredential(tenantID string, clientID string, username st
 	if options == nil {
 		options = &UsernamePasswordCredentialOptions{}
 	}
-	c, err := getPublicClient(clientID, tenantID, &options.ClientOptions, public.WithInstanceDiscovery(!options.DisableInstanceDiscovery))
+	c, err := getPublicClient(clientID, tenantID, &options.ClientOptions, public.WithInstanceDiscovery(!options.DisableInstanceDiscovery), public.WithCache(newCacheAccessor(options.Cache)))
 	if err != nil {
 		return nil, err
 	}
@@ -54,6 +58,9 @@ func (c *UsernamePasswordCredential) GetToken(ctx context.Context, opts policy.T
 	if len(opts.Scopes) == 0 {
 		return azcore.AccessToken{}, errors.New(credNameUserPassword + ": GetToken() requires at least one scope")
 	}
+	if c.account.HomeAccountID == "" {
+		c.account = cachedAccount(c.client, c.username)
+	}
 	ar, err := c.client.AcquireTokenSilent(ctx, opts.Scopes, public.WithSilentAccount(c.account))
 	if err == nil {
 		logGetTokenSuccess(c, opts)
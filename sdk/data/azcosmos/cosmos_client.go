@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	azruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
@@ -44,10 +45,20 @@ func NewClientWithKey(endpoint string, cred KeyCredential, o *ClientOptions) (*C
 // cred - The credential used to authenticate with the cosmos service.
 // options - Optional Cosmos client options.  Pass nil to accept default values.
 func NewClient(endpoint string, cred azcore.TokenCredential, o *ClientOptions) (*Client, error) {
+	var c cloud.Configuration
+	if o != nil {
+		c = o.Cloud
+	}
+	if err := c.ValidateEndpoint(cloud.CosmosDB, endpoint); err != nil {
+		return nil, err
+	}
 	scope, err := createScopeFromEndpoint(endpoint)
 	if err != nil {
 		return nil, err
 	}
+	if conf, ok := c.Services[cloud.CosmosDB]; ok && conf.Audience != "" {
+		scope = []string{strings.TrimSuffix(conf.Audience, "/") + "/.default"}
+	}
 	return &Client{endpoint: endpoint, pipeline: newPipeline(newCosmosBearerTokenPolicy(cred, scope, nil), o)}, nil
 }
 
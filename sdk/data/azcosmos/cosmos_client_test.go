@@ -11,8 +11,10 @@ import (
 	"net/url"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	azruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/mock"
@@ -541,6 +543,31 @@ func TestSendPatch(t *testing.T) {
 	}
 }
 
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestNewClientCloud(t *testing.T) {
+	cred := fakeCredential{}
+	if _, err := NewClient("https://foo.documents.azure.us:443/", cred, &ClientOptions{ClientOptions: azcore.ClientOptions{Cloud: cloud.AzureGovernment}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewClient("https://foo.documents.azure.com:443/", cred, &ClientOptions{ClientOptions: azcore.ClientOptions{Cloud: cloud.AzureGovernment}}); err == nil {
+		t.Fatal("Expected error")
+	}
+
+	if _, err := NewClient("https://localhost:8081/", cred, &ClientOptions{ClientOptions: azcore.ClientOptions{Cloud: cloud.AzureGovernment}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewClient("https://cosmos-emulator:8081/", cred, &ClientOptions{ClientOptions: azcore.ClientOptions{Cloud: cloud.AzureGovernment}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestCreateScopeFromEndpoint(t *testing.T) {
 	url := "https://foo.documents.azure.com:443/"
 	scope, err := createScopeFromEndpoint(url)
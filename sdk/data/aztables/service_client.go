@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
@@ -29,7 +30,11 @@ type ServiceClient struct {
 // Pass in nil for options to construct the client with the default ClientOptions.
 func NewServiceClient(serviceURL string, cred azcore.TokenCredential, options *ClientOptions) (*ServiceClient, error) {
 	conOptions := getConnectionOptions(serviceURL, options)
-	conOptions.PerRetryPolicies = append(conOptions.PerRetryPolicies, runtime.NewBearerTokenPolicy(cred, []string{"https://storage.azure.com/.default"}, nil))
+	scope, err := tokenScope(serviceURL, conOptions.Cloud)
+	if err != nil {
+		return nil, err
+	}
+	conOptions.PerRetryPolicies = append(conOptions.PerRetryPolicies, runtime.NewBearerTokenPolicy(cred, []string{scope}, nil))
 	con := generated.NewConnection(serviceURL, conOptions)
 	return &ServiceClient{
 		client:  generated.NewTableClient(serviceURL, generated.Enum0TwoThousandNineteen0202, conOptions),
@@ -395,6 +400,20 @@ func (t ServiceClient) GetAccountSASURL(resources AccountSASResourceTypes, permi
 	return endpoint, nil
 }
 
+// tokenScope returns the scope of access tokens for serviceURL in cloud c. It returns an error when c
+// has a storage configuration and serviceURL is a storage endpoint in another cloud.
+func tokenScope(serviceURL string, c cloud.Configuration) (string, error) {
+	if !isCosmosEndpoint(serviceURL) {
+		if err := c.ValidateEndpoint(cloud.Storage, serviceURL); err != nil {
+			return "", err
+		}
+	}
+	if conf, ok := c.Services[cloud.Storage]; ok && conf.Audience != "" {
+		return strings.TrimSuffix(conf.Audience, "/") + "/.default", nil
+	}
+	return "https://storage.azure.com/.default", nil
+}
+
 func isCosmosEndpoint(url string) bool {
 	isCosmosEmulator := strings.Contains(url, "localhost") && strings.Contains(url, "8902")
 	return isCosmosEmulator || strings.Contains(url, cosmosTableDomain) || strings.Contains(url, legacyCosmosTableDomain)
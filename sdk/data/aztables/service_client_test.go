@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/recording"
 	"github.com/stretchr/testify/require"
@@ -411,3 +412,27 @@ func TestGetAccountSASTokenError(t *testing.T) {
 	_, err = service.GetAccountSASURL(resources, perms, time.Now(), time.Now().Add(time.Hour))
 	require.Error(t, err)
 }
+
+func TestTokenScope(t *testing.T) {
+	scope, err := tokenScope("https://myaccount.table.core.windows.net", cloud.Configuration{})
+	require.NoError(t, err)
+	require.Equal(t, "https://storage.azure.com/.default", scope)
+
+	scope, err = tokenScope("https://myaccount.table.core.chinacloudapi.cn", cloud.AzureChina)
+	require.NoError(t, err)
+	require.Equal(t, "https://storage.azure.com/.default", scope)
+
+	_, err = tokenScope("https://myaccount.table.core.windows.net", cloud.AzureChina)
+	require.Error(t, err)
+
+	// Cosmos DB endpoints aren't storage endpoints
+	_, err = tokenScope("https://myaccount.table.cosmos.azure.com", cloud.AzurePublic)
+	require.NoError(t, err)
+
+	c := cloud.Configuration{Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+		cloud.Storage: {Audience: "https://storage.contoso.com", Suffix: "storage.contoso.com"},
+	}}
+	scope, err = tokenScope("https://myaccount.table.storage.contoso.com", c)
+	require.NoError(t, err)
+	require.Equal(t, "https://storage.contoso.com/.default", scope)
+}
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/mock"
@@ -254,6 +255,31 @@ func TestDisableChallengeResourceVerification(t *testing.T) {
 	}
 }
 
+func TestNewClientCloud(t *testing.T) {
+	for _, test := range []struct {
+		cloud    cloud.Configuration
+		vaultURL string
+		err      bool
+	}{
+		{cloud: cloud.AzurePublic, vaultURL: "https://myvault.vault.azure.net"},
+		{cloud: cloud.AzureGovernment, vaultURL: "https://myvault.vault.usgovcloudapi.net"},
+		{cloud: cloud.AzureGovernment, vaultURL: "https://myvault.vault.azure.net", err: true},
+		{cloud: cloud.AzureGovernment, vaultURL: "https://myhsm.managedhsm.usgovcloudapi.net"},
+		{vaultURL: "https://myvault.vault.contoso.com"},
+		{cloud: cloud.AzurePublic, vaultURL: "https://keys.contoso.com"},
+	} {
+		t.Run(test.vaultURL, func(t *testing.T) {
+			options := &azkeys.ClientOptions{ClientOptions: policy.ClientOptions{Cloud: test.cloud}}
+			_, err := azkeys.NewClient(test.vaultURL, &FakeCredential{}, options)
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestEncryptDecrypt(t *testing.T) {
 	for _, mhsm := range []bool{false, true} {
 		name := "KV"
@@ -9,7 +9,11 @@ package azkeys
 // this file contains handwritten additions to the generated code
 
 import (
+	"net/url"
+	"strings"
+
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/internal"
@@ -31,6 +35,9 @@ func NewClient(vaultURL string, credential azcore.TokenCredential, options *Clie
 	if options == nil {
 		options = &ClientOptions{}
 	}
+	if err := validateVaultURL(vaultURL, options.Cloud); err != nil {
+		return nil, err
+	}
 	authPolicy := internal.NewKeyVaultChallengePolicy(
 		credential,
 		&internal.KeyVaultChallengePolicyOptions{
@@ -41,6 +48,15 @@ func NewClient(vaultURL string, credential azcore.TokenCredential, options *Clie
 	return &Client{endpoint: vaultURL, pl: pl}, nil
 }
 
+// validateVaultURL returns an error when c has a Key Vault configuration and vaultURL is a Key Vault
+// in another cloud. Managed HSM URLs aren't validated because their DNS suffix differs from Key Vault's.
+func validateVaultURL(vaultURL string, c cloud.Configuration) error {
+	if u, err := url.Parse(vaultURL); err == nil && strings.Contains(strings.ToLower(u.Hostname()), ".managedhsm.") {
+		return nil
+	}
+	return c.ValidateEndpoint(cloud.KeyVault, vaultURL)
+}
+
 // ID is a key's unique ID, containing its version, if any, and name.
 type ID string
 
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/log"
 	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus/internal"
 	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus/internal/amqpwrap"
@@ -56,6 +57,11 @@ type ClientOptions struct {
 	// RetryOptions controls how often operations are retried from this client and any
 	// Receivers and Senders created from this client.
 	RetryOptions RetryOptions
+
+	// Cloud specifies the cloud hosting the namespace. When it has a Service Bus configuration, clients
+	// created with a TokenCredential request tokens for its audience and require the namespace to be in it.
+	// The zero value is Azure Public Cloud.
+	Cloud cloud.Configuration
 }
 
 // RetryOptions controls how often operations are retried from this client and any
@@ -138,9 +144,14 @@ func newClientImpl(creds clientCreds, args clientImplArgs) (*Client, error) {
 	if client.creds.connectionString != "" {
 		nsOptions = append(nsOptions, internal.NamespaceWithConnectionString(client.creds.connectionString))
 	} else if client.creds.credential != nil {
-		option := internal.NamespaceWithTokenCredential(
+		var c cloud.Configuration
+		if args.ClientOptions != nil {
+			c = args.ClientOptions.Cloud
+		}
+		option := internal.NamespaceWithTokenCredentialForCloud(
 			client.creds.fullyQualifiedNamespace,
-			client.creds.credential)
+			client.creds.credential,
+			c)
 
 		nsOptions = append(nsOptions, option)
 	}
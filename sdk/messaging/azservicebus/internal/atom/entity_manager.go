@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus/internal/auth"
@@ -115,7 +116,14 @@ func NewEntityManagerWithConnectionString(connectionString string, version strin
 
 // NewEntityManager creates an entity manager using a TokenCredential.
 func NewEntityManager(ns string, tokenCredential azcore.TokenCredential, version string, options *azcore.ClientOptions) (EntityManager, error) {
-	provider := sbauth.NewTokenProvider(tokenCredential)
+	var c cloud.Configuration
+	if options != nil {
+		c = options.Cloud
+	}
+	provider, err := sbauth.NewTokenProviderForCloud(tokenCredential, ns, c)
+	if err != nil {
+		return nil, err
+	}
 	return newEntityManagerImpl(provider, version, options, ns)
 }
 
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/log"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/telemetry"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/uuid"
@@ -123,8 +124,18 @@ func NamespaceWithWebSocket(newWebSocketConn func(ctx context.Context, args expo
 // NamespaceWithTokenCredential sets the token provider on the namespace
 // fullyQualifiedNamespace is the Service Bus namespace name (ex: myservicebus.servicebus.windows.net)
 func NamespaceWithTokenCredential(fullyQualifiedNamespace string, tokenCredential azcore.TokenCredential) NamespaceOption {
+	return NamespaceWithTokenCredentialForCloud(fullyQualifiedNamespace, tokenCredential, cloud.Configuration{})
+}
+
+// NamespaceWithTokenCredentialForCloud sets the token provider on the namespace, requesting tokens for the
+// Service Bus audience of the cloud c. It fails when fullyQualifiedNamespace isn't in that cloud.
+func NamespaceWithTokenCredentialForCloud(fullyQualifiedNamespace string, tokenCredential azcore.TokenCredential, c cloud.Configuration) NamespaceOption {
 	return func(ns *Namespace) error {
-		ns.TokenProvider = sbauth.NewTokenProvider(tokenCredential)
+		tp, err := sbauth.NewTokenProviderForCloud(tokenCredential, fullyQualifiedNamespace, c)
+		if err != nil {
+			return err
+		}
+		ns.TokenProvider = tp
 		ns.FQDN = fullyQualifiedNamespace
 		return nil
 	}
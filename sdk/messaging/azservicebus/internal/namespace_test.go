@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/telemetry"
 	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus/internal/amqpwrap"
@@ -32,6 +33,37 @@ func (ftc *fakeTokenCredential) GetToken(ctx context.Context, options policy.Tok
 	}, nil
 }
 
+type scopeRecordingCredential struct {
+	azcore.TokenCredential
+	scopes []string
+}
+
+func (c *scopeRecordingCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.scopes = options.Scopes
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestNamespaceWithTokenCredentialForCloud(t *testing.T) {
+	cred := &scopeRecordingCredential{}
+	ns := &Namespace{}
+	err := NamespaceWithTokenCredentialForCloud("mysb.servicebus.windows.net", cred, cloud.AzureGovernment)(ns)
+	require.Error(t, err)
+
+	require.NoError(t, NamespaceWithTokenCredentialForCloud("mysb.servicebus.usgovcloudapi.net", cred, cloud.AzureGovernment)(ns))
+	require.Equal(t, "mysb.servicebus.usgovcloudapi.net", ns.FQDN)
+	_, err = ns.TokenProvider.GetToken("mysb.servicebus.usgovcloudapi.net")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://servicebus.azure.net//.default"}, cred.scopes)
+
+	c := cloud.Configuration{Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+		cloud.ServiceBus: {Audience: "https://servicebus.contoso.com", Suffix: "servicebus.contoso.com"},
+	}}
+	require.NoError(t, NamespaceWithTokenCredentialForCloud("mysb.servicebus.contoso.com", cred, c)(ns))
+	_, err = ns.TokenProvider.GetToken("mysb.servicebus.contoso.com")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://servicebus.contoso.com/.default"}, cred.scopes)
+}
+
 func TestNamespaceUserAgent(t *testing.T) {
 	ns := &Namespace{}
 
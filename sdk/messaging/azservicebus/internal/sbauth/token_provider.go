@@ -9,22 +9,41 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus/internal/auth"
 	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus/internal/conn"
 	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus/internal/sas"
 )
 
+// defaultAudience is the audience of Service Bus access tokens when the cloud doesn't specify one.
+const defaultAudience = "https://servicebus.azure.net/"
+
 // TokenProvider handles access tokens and expiration calculation for SAS
 // keys (via connection strings) or TokenCredentials from Azure Identity.
 type TokenProvider struct {
 	tokenCred        azcore.TokenCredential
 	sasTokenProvider *sas.TokenProvider
+	audience         string
 }
 
 // NewTokenProvider creates a tokenProvider from azcore.TokenCredential.
 func NewTokenProvider(tokenCredential azcore.TokenCredential) *TokenProvider {
-	return &TokenProvider{tokenCred: tokenCredential}
+	return &TokenProvider{tokenCred: tokenCredential, audience: defaultAudience}
+}
+
+// NewTokenProviderForCloud creates a tokenProvider from azcore.TokenCredential for a namespace in the cloud c,
+// requesting tokens for the cloud's Service Bus audience. It returns an error when c has a Service Bus
+// configuration and fullyQualifiedNamespace is in another cloud.
+func NewTokenProviderForCloud(tokenCredential azcore.TokenCredential, fullyQualifiedNamespace string, c cloud.Configuration) (*TokenProvider, error) {
+	if err := c.ValidateEndpoint(cloud.ServiceBus, fullyQualifiedNamespace); err != nil {
+		return nil, err
+	}
+	tp := NewTokenProvider(tokenCredential)
+	if conf, ok := c.Services[cloud.ServiceBus]; ok && conf.Audience != "" {
+		tp.audience = conf.Audience
+	}
+	return tp, nil
 }
 
 // NewTokenProviderWithConnectionString creates a tokenProvider from a connection string.
@@ -91,7 +110,7 @@ func (tpa *TokenProvider) getAZCoreToken() (*auth.Token, time.Time, error) {
 	// not sure if URI plays in here.
 	accessToken, err := tpa.tokenCred.GetToken(context.TODO(), policy.TokenRequestOptions{
 		Scopes: []string{
-			"https://servicebus.azure.net//.default",
+			tpa.audience + "/.default",
 		},
 	})
 
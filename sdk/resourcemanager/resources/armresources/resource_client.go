@@ -0,0 +1,268 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package armresources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+)
+
+// ResourceClientOptions contains the optional values for NewResourceClient.
+type ResourceClientOptions struct {
+	// APIVersions maps resource types such as "Microsoft.Network/virtualNetworks" to the API version the client
+	// uses for them. Resource types are case-insensitive. The client discovers the API version of other types.
+	APIVersions map[string]string
+}
+
+// ResourceClient gets, creates, updates and deletes resources of any type by ID, representing them as raw JSON.
+// Unless specified, it uses the latest stable API version of a resource type, or the latest preview version when
+// the type has no stable version, as listed by the resource provider. API versions are cached per client.
+// Don't use this type directly, use NewResourceClient instead.
+type ResourceClient struct {
+	client *arm.Client
+
+	mu          sync.Mutex
+	apiVersions map[string]string
+}
+
+// NewResourceClient creates a ResourceClient that sends requests with client.
+//   - client - the arm.Client for the Resource Manager endpoint
+//   - options - optional client configurations; pass nil to accept the default values
+func NewResourceClient(client *arm.Client, options *ResourceClientOptions) *ResourceClient {
+	rc := &ResourceClient{client: client, apiVersions: map[string]string{}}
+	if options != nil {
+		for t, v := range options.APIVersions {
+			rc.apiVersions[strings.ToLower(t)] = v
+		}
+	}
+	return rc
+}
+
+// ResourceClientGetOptions contains the optional parameters for the ResourceClient.Get method.
+type ResourceClientGetOptions struct {
+	// APIVersion overrides the API version of the request.
+	APIVersion string
+}
+
+// ResourceClientBeginOptions contains the optional parameters for the ResourceClient methods that return a Poller.
+type ResourceClientBeginOptions struct {
+	// APIVersion overrides the API version of the request.
+	APIVersion string
+
+	// ResumeToken is a token from Poller.ResumeToken. When set, the method resumes polling the operation
+	// instead of starting a new one.
+	ResumeToken string
+}
+
+// ResourceResponse contains the response from ResourceClient methods that return a resource.
+type ResourceResponse struct {
+	// Resource is the resource's JSON.
+	Resource json.RawMessage
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for type ResourceResponse.
+func (r *ResourceResponse) UnmarshalJSON(data []byte) error {
+	r.Resource = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// ResourceClientDeleteResponse contains the response from method ResourceClient.BeginDelete.
+type ResourceClientDeleteResponse struct {
+	// placeholder for future response values
+}
+
+// Get gets a resource.
+// If the operation fails it returns an *azcore.ResponseError type.
+//   - id - the ID of the resource
+//   - options - ResourceClientGetOptions contains the optional parameters for the ResourceClient.Get method
+func (c *ResourceClient) Get(ctx context.Context, id *arm.ResourceID, options *ResourceClientGetOptions) (ResourceResponse, error) {
+	var err error
+	ctx, endSpan := runtime.StartSpan(ctx, "ResourceClient.Get", c.client.Tracer(), nil)
+	defer func() { endSpan(err) }()
+	apiVersion := ""
+	if options != nil {
+		apiVersion = options.APIVersion
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, id, apiVersion, nil)
+	if err != nil {
+		return ResourceResponse{}, err
+	}
+	resp, err := c.client.Pipeline().Do(req)
+	if err != nil {
+		return ResourceResponse{}, err
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		err = runtime.NewResponseError(resp)
+		return ResourceResponse{}, err
+	}
+	body, err := runtime.Payload(resp)
+	if err != nil {
+		return ResourceResponse{}, err
+	}
+	return ResourceResponse{Resource: body}, nil
+}
+
+// BeginCreateOrUpdate creates or replaces a resource.
+// If the operation fails it returns an *azcore.ResponseError type.
+//   - id - the ID of the resource
+//   - resource - the resource's JSON
+//   - options - ResourceClientBeginOptions contains the optional parameters for the method
+func (c *ResourceClient) BeginCreateOrUpdate(ctx context.Context, id *arm.ResourceID, resource json.RawMessage, options *ResourceClientBeginOptions) (*runtime.Poller[ResourceResponse], error) {
+	return begin[ResourceResponse](ctx, c, "ResourceClient.BeginCreateOrUpdate", http.MethodPut, id, resource, options, http.StatusOK, http.StatusCreated, http.StatusAccepted)
+}
+
+// BeginUpdate updates a resource with a JSON merge patch.
+// If the operation fails it returns an *azcore.ResponseError type.
+//   - id - the ID of the resource
+//   - patch - the JSON to merge with the resource
+//   - options - ResourceClientBeginOptions contains the optional parameters for the method
+func (c *ResourceClient) BeginUpdate(ctx context.Context, id *arm.ResourceID, patch json.RawMessage, options *ResourceClientBeginOptions) (*runtime.Poller[ResourceResponse], error) {
+	return begin[ResourceResponse](ctx, c, "ResourceClient.BeginUpdate", http.MethodPatch, id, patch, options, http.StatusOK, http.StatusAccepted)
+}
+
+// BeginDelete deletes a resource.
+// If the operation fails it returns an *azcore.ResponseError type.
+//   - id - the ID of the resource
+//   - options - ResourceClientBeginOptions contains the optional parameters for the method
+func (c *ResourceClient) BeginDelete(ctx context.Context, id *arm.ResourceID, options *ResourceClientBeginOptions) (*runtime.Poller[ResourceClientDeleteResponse], error) {
+	return begin[ResourceClientDeleteResponse](ctx, c, "ResourceClient.BeginDelete", http.MethodDelete, id, nil, options, http.StatusOK, http.StatusAccepted, http.StatusNoContent)
+}
+
+// begin starts a long-running operation, or resumes one when options has a resume token.
+func begin[T any](ctx context.Context, c *ResourceClient, name, method string, id *arm.ResourceID, body json.RawMessage, options *ResourceClientBeginOptions, statusCodes ...int) (*runtime.Poller[T], error) {
+	if options != nil && options.ResumeToken != "" {
+		return runtime.NewPollerFromResumeToken[T](options.ResumeToken, c.client.Pipeline(), nil)
+	}
+	var err error
+	ctx, endSpan := runtime.StartSpan(ctx, name, c.client.Tracer(), nil)
+	defer func() { endSpan(err) }()
+	apiVersion := ""
+	if options != nil {
+		apiVersion = options.APIVersion
+	}
+	req, err := c.newRequest(ctx, method, id, apiVersion, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Pipeline().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if !runtime.HasStatusCode(resp, statusCodes...) {
+		err = runtime.NewResponseError(resp)
+		return nil, err
+	}
+	poller, err := runtime.NewPoller[T](resp, c.client.Pipeline(), nil)
+	return poller, err
+}
+
+func (c *ResourceClient) newRequest(ctx context.Context, method string, id *arm.ResourceID, apiVersion string, body json.RawMessage) (*policy.Request, error) {
+	if id == nil {
+		return nil, errors.New("resource ID can't be nil")
+	}
+	if apiVersion == "" {
+		var err error
+		if apiVersion, err = c.apiVersion(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	req, err := runtime.NewRequest(ctx, method, runtime.JoinPaths(c.client.Endpoint(), id.String()))
+	if err != nil {
+		return nil, err
+	}
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", apiVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+	req.Raw().Header["Accept"] = []string{"application/json"}
+	if body != nil {
+		return req, req.SetBody(streaming.NopCloser(bytes.NewReader(body)), "application/json")
+	}
+	return req, nil
+}
+
+// apiVersion returns the API version for the type of the resource identified by id, fetching the API versions
+// of the type's provider when they aren't cached.
+func (c *ResourceClient) apiVersion(ctx context.Context, id *arm.ResourceID) (string, error) {
+	key := strings.ToLower(id.ResourceType.String())
+	c.mu.Lock()
+	v, ok := c.apiVersions[key]
+	c.mu.Unlock()
+	if ok {
+		return v, nil
+	}
+
+	namespace := id.ResourceType.Namespace
+	providers := &ProvidersClient{host: c.client.Endpoint(), subscriptionID: id.SubscriptionID, pl: c.client.Pipeline()}
+	var provider Provider
+	if id.SubscriptionID != "" {
+		resp, err := providers.Get(ctx, namespace, nil)
+		if err != nil {
+			return "", err
+		}
+		provider = resp.Provider
+	} else {
+		resp, err := providers.GetAtTenantScope(ctx, namespace, nil)
+		if err != nil {
+			return "", err
+		}
+		provider = resp.Provider
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rt := range provider.ResourceTypes {
+		if rt == nil || rt.ResourceType == nil {
+			continue
+		}
+		// the provider lists resource types without their namespace
+		t := strings.ToLower(namespace + "/" + *rt.ResourceType)
+		if _, ok := c.apiVersions[t]; ok {
+			// don't replace versions specified in options
+			continue
+		}
+		if latest := latestAPIVersion(rt.APIVersions); latest != "" {
+			c.apiVersions[t] = latest
+		}
+	}
+	if v, ok := c.apiVersions[key]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("provider %s doesn't list an API version for resource type %s", namespace, id.ResourceType.String())
+}
+
+// latestAPIVersion returns the latest stable version in versions or, when there's no stable version, the latest preview.
+// API versions are dates with an optional suffix such as "-preview", so they sort lexically.
+func latestAPIVersion(versions []*string) string {
+	sorted := make([]string, 0, len(versions))
+	for _, v := range versions {
+		if v != nil {
+			sorted = append(sorted, *v)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(sorted)))
+	for _, v := range sorted {
+		if len(v) == len("2006-01-02") {
+			return v
+		}
+	}
+	if len(sorted) > 0 {
+		return sorted[0]
+	}
+	return ""
+}
@@ -0,0 +1,210 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package armresources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/internal/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testProvider = `{
+	"namespace": "Microsoft.Network",
+	"resourceTypes": [
+		{ "resourceType": "virtualNetworks", "apiVersions": ["2022-07-01", "2022-09-01-preview", "2022-05-01"] },
+		{ "resourceType": "virtualNetworks/subnets", "apiVersions": ["2022-11-01-preview", "2022-10-01-preview"] }
+	]
+}`
+
+const testSubscriptionID = "00000000-0000-0000-0000-000000000000"
+
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "***", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+type transportFunc func(*http.Request) (*http.Response, error)
+
+func (tf transportFunc) Do(req *http.Request) (*http.Response, error) {
+	return tf(req)
+}
+
+type requestRecorder struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *requestRecorder) methods() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	methods := make([]string, len(r.requests))
+	for i, req := range r.requests {
+		methods[i] = req.Method + " " + req.URL.Path + "?" + req.URL.RawQuery
+	}
+	return methods
+}
+
+func newTestResourceClient(t *testing.T, srv *mock.Server, options *ResourceClientOptions) (*ResourceClient, *requestRecorder) {
+	rec := &requestRecorder{}
+	client, err := arm.NewClient(moduleName+".ResourceClient", moduleVersion, fakeCredential{}, &arm.ClientOptions{
+		ClientOptions: azcore.ClientOptions{
+			Cloud: cloud.Configuration{
+				Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+					cloud.ResourceManager: {Audience: "https://management.core.windows.net", Endpoint: srv.URL()},
+				},
+			},
+			Transport: transportFunc(func(req *http.Request) (*http.Response, error) {
+				var body []byte
+				if req.Body != nil {
+					var err error
+					if body, err = io.ReadAll(req.Body); err != nil {
+						return nil, err
+					}
+					req.Body = io.NopCloser(bytes.NewReader(body))
+				}
+				rec.mu.Lock()
+				rec.requests = append(rec.requests, req)
+				rec.bodies = append(rec.bodies, body)
+				rec.mu.Unlock()
+				return srv.Do(req)
+			}),
+		},
+	})
+	require.NoError(t, err)
+	return NewResourceClient(client, options), rec
+}
+
+func testVnetID() *arm.ResourceID {
+	return arm.NewSubscriptionID(testSubscriptionID).ResourceGroup("myRg").ProviderNamespace("Microsoft.Network").Child("virtualNetworks", "myVnet")
+}
+
+func TestResourceClientGet(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithBody([]byte(testProvider)))
+	srv.AppendResponse(mock.WithBody([]byte(`{"name":"myVnet"}`)))
+	srv.AppendResponse(mock.WithBody([]byte(`{"name":"mySubnet"}`)))
+
+	client, rec := newTestResourceClient(t, srv, nil)
+	resp, err := client.Get(context.Background(), testVnetID(), nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"myVnet"}`, string(resp.Resource))
+
+	// the subnet's API version should be cached
+	resp, err = client.Get(context.Background(), testVnetID().Child("subnets", "mySubnet"), nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"mySubnet"}`, string(resp.Resource))
+
+	require.Equal(t, []string{
+		"GET /subscriptions/" + testSubscriptionID + "/providers/Microsoft.Network?api-version=2021-04-01",
+		"GET " + testVnetID().String() + "?api-version=2022-07-01",
+		"GET " + testVnetID().String() + "/subnets/mySubnet?api-version=2022-11-01-preview",
+	}, rec.methods())
+}
+
+func TestResourceClientAPIVersions(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithBody([]byte(`{}`)))
+	srv.AppendResponse(mock.WithBody([]byte(`{}`)))
+
+	client, rec := newTestResourceClient(t, srv, &ResourceClientOptions{
+		APIVersions: map[string]string{"microsoft.network/VIRTUALNETWORKS": "2020-01-01"},
+	})
+	_, err := client.Get(context.Background(), testVnetID(), nil)
+	require.NoError(t, err)
+	_, err = client.Get(context.Background(), testVnetID(), &ResourceClientGetOptions{APIVersion: "2021-01-01"})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"GET " + testVnetID().String() + "?api-version=2020-01-01",
+		"GET " + testVnetID().String() + "?api-version=2021-01-01",
+	}, rec.methods())
+}
+
+func TestResourceClientUnknownType(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithBody([]byte(testProvider)))
+
+	client, _ := newTestResourceClient(t, srv, nil)
+	_, err := client.Get(context.Background(), testVnetID().Child("unknown", "name"), nil)
+	require.Error(t, err)
+}
+
+func TestResourceClientGetError(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithStatusCode(http.StatusNotFound), mock.WithBody([]byte(`{"error":{"code":"ResourceNotFound"}}`)))
+
+	client, _ := newTestResourceClient(t, srv, &ResourceClientOptions{APIVersions: map[string]string{"Microsoft.Network/virtualNetworks": "2022-07-01"}})
+	_, err := client.Get(context.Background(), testVnetID(), nil)
+	var respErr *azcore.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, "ResourceNotFound", respErr.ErrorCode)
+}
+
+func TestResourceClientBeginCreateOrUpdate(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithBody([]byte(`{"name":"myVnet","properties":{"provisioningState":"Succeeded"}}`)))
+
+	client, rec := newTestResourceClient(t, srv, &ResourceClientOptions{APIVersions: map[string]string{"Microsoft.Network/virtualNetworks": "2022-07-01"}})
+	poller, err := client.BeginCreateOrUpdate(context.Background(), testVnetID(), json.RawMessage(`{"location":"westus"}`), nil)
+	require.NoError(t, err)
+	require.True(t, poller.Done())
+	resp, err := poller.Result(context.Background())
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"myVnet","properties":{"provisioningState":"Succeeded"}}`, string(resp.Resource))
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	require.Len(t, rec.requests, 1)
+	require.JSONEq(t, `{"location":"westus"}`, string(rec.bodies[0]))
+	require.Equal(t, "application/json", rec.requests[0].Header.Get("Content-Type"))
+}
+
+func TestResourceClientBeginDelete(t *testing.T) {
+	srv, close := mock.NewServer()
+	defer close()
+	srv.AppendResponse(mock.WithStatusCode(http.StatusAccepted), mock.WithHeader("Azure-AsyncOperation", srv.URL()+"/operation"))
+	srv.AppendResponse(mock.WithBody([]byte(`{"status":"Succeeded"}`)))
+
+	client, _ := newTestResourceClient(t, srv, &ResourceClientOptions{APIVersions: map[string]string{"Microsoft.Network/virtualNetworks": "2022-07-01"}})
+	poller, err := client.BeginDelete(context.Background(), testVnetID(), nil)
+	require.NoError(t, err)
+	require.False(t, poller.Done())
+	token, err := poller.ResumeToken()
+	require.NoError(t, err)
+
+	poller, err = client.BeginDelete(context.Background(), testVnetID(), &ResourceClientBeginOptions{ResumeToken: token})
+	require.NoError(t, err)
+	_, err = poller.PollUntilDone(context.Background(), &runtime.PollUntilDoneOptions{Frequency: time.Second})
+	require.NoError(t, err)
+}
+
+func TestLatestAPIVersion(t *testing.T) {
+	require.Equal(t, "2022-07-01", latestAPIVersion(to.SliceOfPtrs("2022-05-01", "2022-09-01-preview", "2022-07-01")))
+	require.Equal(t, "2022-11-01-preview", latestAPIVersion(to.SliceOfPtrs("2022-10-01-preview", "2022-11-01-preview")))
+	require.Equal(t, "2022-05-01", latestAPIVersion(to.SliceOfPtrs("2022-09-01-beta", "2022-05-01")))
+	require.Empty(t, latestAPIVersion(nil))
+}
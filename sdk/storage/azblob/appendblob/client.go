@@ -34,8 +34,12 @@ type Client base.CompositeClient[generated.BlobClient, generated.AppendBlobClien
 //   - cred - an Azure AD credential, typically obtained via the azidentity module
 //   - options - client options; pass nil to accept the default values
 func NewClient(blobURL string, cred azcore.TokenCredential, options *ClientOptions) (*Client, error) {
-	authPolicy := runtime.NewBearerTokenPolicy(cred, []string{shared.TokenScope}, nil)
 	conOptions := shared.GetClientOptions(options)
+	scope, err := shared.GetTokenScope(blobURL, conOptions.Cloud)
+	if err != nil {
+		return nil, err
+	}
+	authPolicy := runtime.NewBearerTokenPolicy(cred, []string{scope}, nil)
 	conOptions.PerRetryPolicies = append(conOptions.PerRetryPolicies, authPolicy)
 	pl := runtime.NewPipeline(exported.ModuleName,
 		exported.ModuleVersion, runtime.PipelineOptions{},
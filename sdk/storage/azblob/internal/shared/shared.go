@@ -16,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/internal/uuid"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/internal/generated"
@@ -223,6 +224,18 @@ func GetClientOptions[T any](o *T) *T {
 	return o
 }
 
+// GetTokenScope returns the scope of access tokens for the storage endpoint u in cloud c. It returns an
+// error when c has a storage configuration and u is a storage endpoint in another cloud.
+func GetTokenScope(u string, c cloud.Configuration) (string, error) {
+	if err := c.ValidateEndpoint(cloud.Storage, u); err != nil {
+		return "", err
+	}
+	if conf, ok := c.Services[cloud.Storage]; ok && conf.Audience != "" {
+		return strings.TrimSuffix(conf.Audience, "/") + "/.default", nil
+	}
+	return TokenScope, nil
+}
+
 // IsIPEndpointStyle checkes if URL's host is IP, in this case the storage account endpoint will be composed as:
 // http(s)://IP(:port)/storageaccount/container/...
 // As url's Host property, host could be both host or host:port
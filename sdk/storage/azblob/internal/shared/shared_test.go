@@ -9,6 +9,7 @@ package shared
 import (
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/stretchr/testify/require"
 )
 
@@ -94,3 +95,30 @@ func TestCParseConnectionStringAzurite(t *testing.T) {
 	require.Equal(t, "dummyaccountname", parsed.AccountName)
 	require.Equal(t, "secretkeykey", parsed.AccountKey)
 }
+
+func TestGetTokenScope(t *testing.T) {
+	scope, err := GetTokenScope("https://dummyaccount.blob.core.windows.net/container", cloud.Configuration{})
+	require.NoError(t, err)
+	require.Equal(t, TokenScope, scope)
+
+	scope, err = GetTokenScope("https://dummyaccount.blob.core.usgovcloudapi.net/container", cloud.AzureGovernment)
+	require.NoError(t, err)
+	require.Equal(t, TokenScope, scope)
+
+	_, err = GetTokenScope("https://dummyaccount.blob.core.windows.net/container", cloud.AzureGovernment)
+	require.Error(t, err)
+
+	// hosts outside the known clouds' storage domains, such as custom domains and emulators, are valid
+	for _, u := range []string{"https://storage.contoso.com/container", "http://azurite:10000/devstoreaccount1"} {
+		scope, err = GetTokenScope(u, cloud.AzureGovernment)
+		require.NoError(t, err)
+		require.Equal(t, TokenScope, scope)
+	}
+
+	c := cloud.Configuration{Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+		cloud.Storage: {Audience: "https://storage.contoso.com/", Suffix: "storage.contoso.com"},
+	}}
+	scope, err = GetTokenScope("https://dummyaccount.blob.storage.contoso.com", c)
+	require.NoError(t, err)
+	require.Equal(t, "https://storage.contoso.com/.default", scope)
+}
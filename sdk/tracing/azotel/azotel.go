@@ -0,0 +1,130 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package azotel provides an adapter from an OpenTelemetry TracerProvider to an azcore tracing.Provider.
+package azotel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingProviderOptions contains the optional values for NewTracingProvider.
+type TracingProviderOptions struct {
+	// Propagator is used to propagate the active span context in the headers of outgoing HTTP requests.
+	// The default value is propagation.TraceContext, which writes the W3C traceparent and tracestate headers.
+	Propagator propagation.TextMapPropagator
+}
+
+// NewTracingProvider creates a new tracing.Provider that wraps the specified OpenTelemetry TracerProvider.
+//   - tracerProvider - the TracerProvider to wrap
+//   - options - optional configuration. pass nil to accept the default values
+func NewTracingProvider(tracerProvider trace.TracerProvider, options *TracingProviderOptions) tracing.Provider {
+	if options == nil {
+		options = &TracingProviderOptions{}
+	}
+	propagator := options.Propagator
+	if propagator == nil {
+		propagator = propagation.TraceContext{}
+	}
+	return tracing.NewProvider(func(name, version string) tracing.Tracer {
+		tracer := tracerProvider.Tracer(name, trace.WithInstrumentationVersion(version))
+
+		return tracing.NewTracer(func(ctx context.Context, spanName string, options *tracing.SpanOptions) (context.Context, tracing.Span) {
+			kind := tracing.SpanKindInternal
+			var attrs []attribute.KeyValue
+			if options != nil {
+				if options.Kind != 0 {
+					kind = options.Kind
+				}
+				attrs = convertAttributes(options.Attributes)
+			}
+			ctx, span := tracer.Start(ctx, spanName, trace.WithSpanKind(convertSpanKind(kind)), trace.WithAttributes(attrs...))
+			return ctx, convertSpan(span)
+		}, &tracing.TracerOptions{
+			Inject: func(ctx context.Context, header http.Header) {
+				propagator.Inject(ctx, propagation.HeaderCarrier(header))
+			},
+		})
+	}, nil)
+}
+
+func convertSpan(span trace.Span) tracing.Span {
+	return tracing.NewSpan(tracing.SpanImpl{
+		End: func() { span.End() },
+		SetAttributes: func(attrs ...tracing.Attribute) {
+			span.SetAttributes(convertAttributes(attrs)...)
+		},
+		AddEvent: func(name string, attrs ...tracing.Attribute) {
+			span.AddEvent(name, trace.WithAttributes(convertAttributes(attrs)...))
+		},
+		AddError: func(err error) {
+			span.RecordError(err)
+		},
+		SetStatus: func(code tracing.SpanStatus, desc string) {
+			span.SetStatus(convertStatus(code), desc)
+		},
+	})
+}
+
+func convertAttributes(attrs []tracing.Attribute) []attribute.KeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	otelAttrs := make([]attribute.KeyValue, len(attrs))
+	for i, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case int64:
+			otelAttrs[i] = attribute.Int64(attr.Key, v)
+		case int:
+			otelAttrs[i] = attribute.Int(attr.Key, v)
+		case float64:
+			otelAttrs[i] = attribute.Float64(attr.Key, v)
+		case bool:
+			otelAttrs[i] = attribute.Bool(attr.Key, v)
+		case string:
+			otelAttrs[i] = attribute.String(attr.Key, v)
+		default:
+			otelAttrs[i] = attribute.String(attr.Key, fmt.Sprintf("%v", v))
+		}
+	}
+	return otelAttrs
+}
+
+func convertSpanKind(sk tracing.SpanKind) trace.SpanKind {
+	switch sk {
+	case tracing.SpanKindClient:
+		return trace.SpanKindClient
+	case tracing.SpanKindConsumer:
+		return trace.SpanKindConsumer
+	case tracing.SpanKindInternal:
+		return trace.SpanKindInternal
+	case tracing.SpanKindProducer:
+		return trace.SpanKindProducer
+	case tracing.SpanKindServer:
+		return trace.SpanKindServer
+	default:
+		return trace.SpanKindUnspecified
+	}
+}
+
+func convertStatus(ss tracing.SpanStatus) codes.Code {
+	switch ss {
+	case tracing.SpanStatusError:
+		return codes.Error
+	case tracing.SpanStatusOK:
+		return codes.Ok
+	default:
+		return codes.Unset
+	}
+}
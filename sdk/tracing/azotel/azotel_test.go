@@ -0,0 +1,151 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azotel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestProvider(options *TracingProviderOptions) (tracing.Provider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return NewTracingProvider(tp, options), exporter
+}
+
+func TestNewTracingProvider(t *testing.T) {
+	provider, exporter := newTestProvider(nil)
+	tracer := provider.NewTracer("azotel.Client", "v1.0.0")
+	require.True(t, tracer.Enabled())
+
+	ctx, span := tracer.Start(context.Background(), "Client.Method", &tracing.SpanOptions{
+		Kind: tracing.SpanKindClient,
+		Attributes: []tracing.Attribute{
+			{Key: "int", Value: 1},
+			{Key: "int64", Value: int64(2)},
+			{Key: "float64", Value: 3.5},
+			{Key: "bool", Value: true},
+			{Key: "string", Value: "value"},
+			{Key: "other", Value: []int{1, 2}},
+		},
+	})
+	require.True(t, trace.SpanFromContext(ctx).SpanContext().IsValid())
+	span.SetAttributes(tracing.Attribute{Key: "later", Value: "set"})
+	span.AddEvent("event", tracing.Attribute{Key: "event-attr", Value: 1})
+	span.AddError(errors.New("boom"))
+	span.SetStatus(tracing.SpanStatusError, "failed")
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	stub := spans[0]
+	require.Equal(t, "Client.Method", stub.Name)
+	require.Equal(t, trace.SpanKindClient, stub.SpanKind)
+	require.Equal(t, "azotel.Client", stub.InstrumentationLibrary.Name)
+	require.Equal(t, "v1.0.0", stub.InstrumentationLibrary.Version)
+	require.ElementsMatch(t, []attribute.KeyValue{
+		attribute.Int("int", 1),
+		attribute.Int64("int64", 2),
+		attribute.Float64("float64", 3.5),
+		attribute.Bool("bool", true),
+		attribute.String("string", "value"),
+		attribute.String("other", "[1 2]"),
+		attribute.String("later", "set"),
+	}, stub.Attributes)
+	require.Equal(t, codes.Error, stub.Status.Code)
+	require.Equal(t, "failed", stub.Status.Description)
+	require.Len(t, stub.Events, 2)
+	require.Equal(t, "event", stub.Events[0].Name)
+	require.Equal(t, "exception", stub.Events[1].Name)
+}
+
+func TestNewTracingProviderDefaultKind(t *testing.T) {
+	provider, exporter := newTestProvider(nil)
+	_, span := provider.NewTracer("azotel.Client", "v1.0.0").Start(context.Background(), "name", nil)
+	span.SetStatus(tracing.SpanStatusOK, "")
+	span.End()
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, trace.SpanKindInternal, spans[0].SpanKind)
+	require.Equal(t, codes.Ok, spans[0].Status.Code)
+}
+
+func TestInject(t *testing.T) {
+	provider, _ := newTestProvider(nil)
+	tracer := provider.NewTracer("azotel.Client", "v1.0.0")
+
+	// no active span
+	header := http.Header{}
+	tracer.Inject(context.Background(), header)
+	require.Empty(t, header)
+
+	ctx, span := tracer.Start(context.Background(), "name", nil)
+	defer span.End()
+	tracer.Inject(ctx, header)
+	sc := trace.SpanFromContext(ctx).SpanContext()
+	require.Equal(t, "00-"+sc.TraceID().String()+"-"+sc.SpanID().String()+"-01", header.Get("traceparent"))
+}
+
+func TestInjectCustomPropagator(t *testing.T) {
+	provider, _ := newTestProvider(&TracingProviderOptions{Propagator: propagation.Baggage{}})
+	tracer := provider.NewTracer("azotel.Client", "v1.0.0")
+	ctx, span := tracer.Start(context.Background(), "name", nil)
+	defer span.End()
+	header := http.Header{}
+	tracer.Inject(ctx, header)
+	require.Empty(t, header.Get("traceparent"))
+}
+
+func TestPipeline(t *testing.T) {
+	provider, exporter := newTestProvider(nil)
+	var traceparent string
+	pl := runtime.NewPipeline("azotel", "v1.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		TracingProvider: provider,
+		Transport: transportFunc(func(req *http.Request) (*http.Response, error) {
+			traceparent = req.Header.Get("traceparent")
+			return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+		}),
+	})
+
+	ctx, endSpan := runtime.StartSpan(context.Background(), "Client.Method", provider.NewTracer("azotel.Client", "v1.0.0"), nil)
+	req, err := runtime.NewRequest(ctx, http.MethodGet, "https://contoso.com/path?secret=value")
+	require.NoError(t, err)
+	_, err = pl.Do(req)
+	require.NoError(t, err)
+	endSpan(nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	httpSpan, opSpan := spans[0], spans[1]
+	require.Equal(t, "HTTP GET", httpSpan.Name)
+	require.Equal(t, trace.SpanKindClient, httpSpan.SpanKind)
+	require.Equal(t, opSpan.SpanContext.SpanID(), httpSpan.Parent.SpanID())
+	require.Contains(t, httpSpan.Attributes, attribute.String("http.url", "https://contoso.com/path?secret=REDACTED"))
+	require.Contains(t, httpSpan.Attributes, attribute.Int("http.status_code", http.StatusOK))
+	require.Equal(t, "00-"+httpSpan.SpanContext.TraceID().String()+"-"+httpSpan.SpanContext.SpanID().String()+"-01", traceparent)
+	require.Equal(t, "Client.Method", opSpan.Name)
+	require.Equal(t, trace.SpanKindInternal, opSpan.SpanKind)
+}
+
+type transportFunc func(*http.Request) (*http.Response, error)
+
+func (pf transportFunc) Do(req *http.Request) (*http.Response, error) {
+	return pf(req)
+}